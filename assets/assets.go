@@ -0,0 +1,222 @@
+// Package assets собирает файловую систему ресурсов игры из слоёв: встроенный
+// в бинарник набор по умолчанию, каталог ресурсов на диске и каталоги модов.
+// Файл из верхнего слоя перекрывает одноимённый файл нижних слоёв, поэтому
+// мод может заменить отдельный шрифт или таблицу строк, не копируя остальное.
+package assets
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Встроенный набор — всё, без чего игра не запустится. Фоновое видео, ролики
+// и музыка слишком велики для бинарника и читаются только с диска; без них
+// меню показывает background.png, новая игра начинается без вступления, а
+// музыки нет.
+//
+//go:embed AethelgardFont.ttf background.png fonts locales maps sfx sprites
+var embedded embed.FS
+
+// DefaultDir — каталог ресурсов на диске, если он не задан флагом --assets
+const DefaultDir = "assets"
+
+// EmbeddedLayer — имя слоя встроенных ресурсов в Layers и Source
+const EmbeddedLayer = "embedded"
+
+type layer struct {
+	name string
+	fsys fs.FS
+}
+
+// FS — слоистая файловая система ресурсов, слои упорядочены от верхнего к нижнему
+type FS struct {
+	layers []layer
+}
+
+// Embedded возвращает только встроенные ресурсы
+func Embedded() fs.FS {
+	return embedded
+}
+
+// Open собирает файловую систему ресурсов. root — каталог на диске; пустой
+// root означает DefaultDir рядом с рабочим каталогом или с исполняемым файлом,
+// и его отсутствие не ошибка. Явно заданный, но отсутствующий root — ошибка.
+// Подкаталоги modsDir подключаются как моды в порядке имён: более поздний мод
+// перекрывает более ранний.
+func Open(root, modsDir string) (*FS, error) {
+	a := &FS{}
+
+	if modsDir != "" {
+		mods, err := modDirs(modsDir)
+		if err != nil {
+			log.Printf("Warning: failed to read mods directory %s: %v", modsDir, err)
+		}
+		for i := len(mods) - 1; i >= 0; i-- {
+			a.layers = append(a.layers, layer{"mod:" + filepath.Base(mods[i]), os.DirFS(mods[i])})
+		}
+	}
+
+	if root != "" {
+		if !isDir(root) {
+			return nil, fmt.Errorf("assets: directory %s not found", root)
+		}
+		a.layers = append(a.layers, layer{root, os.DirFS(root)})
+	} else if dir := findDefaultDir(); dir != "" {
+		a.layers = append(a.layers, layer{dir, os.DirFS(dir)})
+	}
+
+	a.layers = append(a.layers, layer{EmbeddedLayer, embedded})
+	return a, nil
+}
+
+// modDirs возвращает каталоги модов, отсортированные по имени
+func modDirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var mods []string
+	for _, e := range entries {
+		if e.IsDir() {
+			mods = append(mods, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(mods)
+	return mods, nil
+}
+
+// findDefaultDir ищет DefaultDir в рабочем каталоге, затем рядом с исполняемым файлом
+func findDefaultDir() string {
+	if isDir(DefaultDir) {
+		return DefaultDir
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	if dir := filepath.Join(filepath.Dir(exe), DefaultDir); isDir(dir) {
+		return dir
+	}
+	return ""
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// Layers возвращает имена слоёв от верхнего к нижнему
+func (a *FS) Layers() []string {
+	names := make([]string, len(a.layers))
+	for i, l := range a.layers {
+		names[i] = l.name
+	}
+	return names
+}
+
+// Source возвращает имя слоя, из которого будет прочитан файл
+func (a *FS) Source(name string) (string, error) {
+	for _, l := range a.layers {
+		if _, err := fs.Stat(l.fsys, name); err == nil {
+			return l.name, nil
+		}
+	}
+	return "", &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// Open открывает файл из верхнего слоя, где он есть
+func (a *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for _, l := range a.layers {
+		f, err := l.fsys.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !isNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadFile читает файл из верхнего слоя, где он есть
+func (a *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for _, l := range a.layers {
+		data, err := fs.ReadFile(l.fsys, name)
+		if err == nil {
+			return data, nil
+		}
+		if !isNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+}
+
+// Stat возвращает сведения о файле из верхнего слоя, где он есть
+func (a *FS) Stat(name string) (fs.FileInfo, error) {
+	for _, l := range a.layers {
+		info, err := fs.Stat(l.fsys, name)
+		if err == nil {
+			return info, nil
+		}
+		if !isNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir объединяет содержимое каталога во всех слоях; при совпадении имён
+// берётся запись верхнего слоя. Записи отсортированы по имени.
+func (a *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	seen := make(map[string]bool)
+	var entries []fs.DirEntry
+	found := false
+
+	for _, l := range a.layers {
+		list, err := fs.ReadDir(l.fsys, name)
+		if err != nil {
+			if isNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		found = true
+		for _, e := range list {
+			if !seen[e.Name()] {
+				seen[e.Name()] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+func isNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
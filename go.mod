@@ -3,6 +3,7 @@ module aethelgard
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.2.0
 	github.com/hajimehoshi/ebiten/v2 v2.4.0
 	golang.org/x/image v0.10.0
 )
@@ -14,6 +15,8 @@ require (
 	github.com/hajimehoshi/go-mp3 v0.3.3 // indirect
 	github.com/hajimehoshi/oto/v2 v2.3.0 // indirect
 	github.com/jezek/xgb v1.2.0 // indirect
+	github.com/jfreymuth/oggvorbis v1.0.5 // indirect
+	github.com/jfreymuth/vorbis v1.0.2 // indirect
 	golang.org/x/exp v0.0.0-20190731235908-ec7cb31e5a56 // indirect
 	golang.org/x/mobile v0.0.0-20220722155234-aaac322e2105 // indirect
 	golang.org/x/sys v0.5.0 // indirect
@@ -0,0 +1,246 @@
+// Package anim — покадровая анимация персонажей: именованные анимации из
+// списка кадров с длительностями, режимами повтора и вариантами по
+// направлениям, машина состояний, которая переключает их по условиям,
+// и метки событий на кадрах (шаг, удар), через которые анимация зовёт
+// другие системы. Пакет знает только номера кадров и не зависит от ebiten;
+// картинки кадров хранит пакет sprite.
+package anim
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Mode — что делает анимация, дойдя до последнего кадра
+type Mode int
+
+const (
+	// Loop начинает сначала
+	Loop Mode = iota
+	// PingPong идёт обратно к первому кадру и снова вперёд
+	PingPong
+	// Once останавливается на последнем кадре
+	Once
+)
+
+var modeNames = [...]string{"loop", "ping_pong", "once"}
+
+func (m Mode) String() string {
+	if m < 0 || int(m) >= len(modeNames) {
+		return "unknown"
+	}
+	return modeNames[m]
+}
+
+// MarshalText записывает режим именем
+func (m Mode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText читает режим по имени
+func (m *Mode) UnmarshalText(text []byte) error {
+	for i, name := range modeNames {
+		if name == string(text) {
+			*m = Mode(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown mode %q", text)
+}
+
+// DefaultDuration — длительность кадра без явной, в секундах
+const DefaultDuration = 0.1
+
+// Frame — кадр анимации: номер картинки в наборе, сколько секунд он
+// показывается и какие события срабатывают, когда анимация на него входит
+type Frame struct {
+	Index    int
+	Duration float64
+	Events   []string
+}
+
+// Clip — кадры одного направления анимации. FlipX — картинки отражаются
+// по горизонтали, так вид влево получается из вида вправо.
+type Clip struct {
+	Frames []Frame
+	FlipX  bool
+}
+
+// Animation — именованная анимация: режим повтора и кадры по направлениям.
+// Ключ Variants — имя направления как у player.Direction ("down",
+// "up_left"...), пустой ключ — анимация без направлений.
+type Animation struct {
+	Name     string
+	Mode     Mode
+	Variants map[string]*Clip
+}
+
+// Clip возвращает кадры для направления. Если точного варианта нет,
+// диагональ берёт свою горизонтальную часть, затем вертикальную, а дальше
+// идут вариант без направления, «вниз» и первый по имени.
+func (a *Animation) Clip(dir string) *Clip {
+	if c, ok := a.Variants[dir]; ok {
+		return c
+	}
+	if v, h, ok := strings.Cut(dir, "_"); ok {
+		if c, ok := a.Variants[h]; ok {
+			return c
+		}
+		if c, ok := a.Variants[v]; ok {
+			return c
+		}
+	}
+	for _, name := range []string{"", "down"} {
+		if c, ok := a.Variants[name]; ok {
+			return c
+		}
+	}
+	names := make([]string, 0, len(a.Variants))
+	for name := range a.Variants {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return a.Variants[names[0]]
+}
+
+// Set — анимации одного персонажа и машина состояний, которая ими управляет
+type Set struct {
+	Name       string
+	Animations map[string]*Animation
+	Machine    *Machine
+}
+
+// Library — наборы анимаций по имени; ресурс мира сущностей
+type Library map[string]*Set
+
+// rawClip — кадры в файле: номера картинок, длительность кадра
+// в миллисекундах (как у анимаций Tiled) или своя для каждого кадра,
+// события по номеру кадра в списке. Mirror берёт кадры другого
+// направления и отражает их.
+type rawClip struct {
+	Frames    []int               `json:"frames"`
+	Duration  float64             `json:"duration"`
+	Durations []float64           `json:"durations"`
+	Events    map[string][]string `json:"events"`
+	FlipX     bool                `json:"flip_x"`
+	Mirror    string              `json:"mirror"`
+}
+
+// rawAnimation — анимация в файле. Кадры верхнего уровня — вариант без
+// направления; у вариантов directions длительности и события, если их нет,
+// берутся с верхнего уровня.
+type rawAnimation struct {
+	rawClip
+	Mode       Mode               `json:"mode"`
+	Directions map[string]rawClip `json:"directions"`
+}
+
+type rawSet struct {
+	Animations map[string]rawAnimation `json:"animations"`
+	Machine    *Machine                `json:"machine"`
+}
+
+// Parse читает анимации и машину состояний из описания спрайта; прочие
+// поля файла (листы кадров) читает пакет sprite. frames — число картинок
+// в наборе, номера кадров за его пределами — ошибка.
+func Parse(name string, data []byte, frames int) (*Set, error) {
+	var raw rawSet
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("anim: %s: %w", name, err)
+	}
+
+	s := &Set{Name: name, Animations: make(map[string]*Animation, len(raw.Animations)), Machine: raw.Machine}
+	for an, ra := range raw.Animations {
+		a := &Animation{Name: an, Mode: ra.Mode, Variants: make(map[string]*Clip)}
+		if len(ra.Frames) > 0 {
+			c, err := ra.rawClip.clip(ra.rawClip, frames)
+			if err != nil {
+				return nil, fmt.Errorf("anim: %s: animation %q: %w", name, an, err)
+			}
+			a.Variants[""] = c
+		}
+
+		// Сначала варианты со своими кадрами, затем отражения
+		for pass := 0; pass < 2; pass++ {
+			for dir, rc := range ra.Directions {
+				if (rc.Mirror != "") != (pass == 1) {
+					continue
+				}
+				if rc.Mirror != "" {
+					src, ok := a.Variants[rc.Mirror]
+					if !ok || ra.Directions[rc.Mirror].Mirror != "" {
+						return nil, fmt.Errorf("anim: %s: animation %q: direction %q mirrors missing %q", name, an, dir, rc.Mirror)
+					}
+					a.Variants[dir] = &Clip{Frames: src.Frames, FlipX: !src.FlipX}
+					continue
+				}
+				c, err := rc.clip(ra.rawClip, frames)
+				if err != nil {
+					return nil, fmt.Errorf("anim: %s: animation %q, direction %q: %w", name, an, dir, err)
+				}
+				a.Variants[dir] = c
+			}
+		}
+		if len(a.Variants) == 0 {
+			return nil, fmt.Errorf("anim: %s: animation %q has no frames", name, an)
+		}
+		s.Animations[an] = a
+	}
+
+	if s.Machine != nil {
+		if err := s.Machine.validate(s); err != nil {
+			return nil, fmt.Errorf("anim: %s: %w", name, err)
+		}
+	}
+	return s, nil
+}
+
+// clip собирает кадры варианта; пустые длительности и события берутся
+// из def
+func (rc rawClip) clip(def rawClip, frames int) (*Clip, error) {
+	if len(rc.Frames) == 0 {
+		return nil, fmt.Errorf("no frames")
+	}
+	if rc.Duration == 0 {
+		rc.Duration = def.Duration
+	}
+	if rc.Durations == nil {
+		rc.Durations = def.Durations
+	}
+	if rc.Events == nil {
+		rc.Events = def.Events
+	}
+	if rc.Durations != nil && len(rc.Durations) != len(rc.Frames) {
+		return nil, fmt.Errorf("%d durations for %d frames", len(rc.Durations), len(rc.Frames))
+	}
+
+	c := &Clip{Frames: make([]Frame, len(rc.Frames)), FlipX: rc.FlipX}
+	for i, index := range rc.Frames {
+		if index < 0 || index >= frames {
+			return nil, fmt.Errorf("frame %d out of range [0, %d)", index, frames)
+		}
+		ms := rc.Duration
+		if rc.Durations != nil {
+			ms = rc.Durations[i]
+		}
+		d := ms / 1000
+		if d <= 0 {
+			d = DefaultDuration
+		}
+		c.Frames[i] = Frame{Index: index, Duration: d}
+	}
+	for key, events := range rc.Events {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(c.Frames) {
+			return nil, fmt.Errorf("events on missing frame %q", key)
+		}
+		c.Frames[i].Events = events
+	}
+	return c, nil
+}
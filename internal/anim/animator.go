@@ -0,0 +1,200 @@
+package anim
+
+// Animator — проигрывание набора анимаций для одного персонажа: текущее
+// состояние машины, направление и место в кадрах. Всё, кроме триггеров,
+// переживает JSON, поэтому после загрузки анимация продолжается с того же
+// кадра.
+type Animator struct {
+	Set   string `json:"set"`
+	State string `json:"state"`
+	Dir   string `json:"dir"`
+
+	// Frame — номер кадра в списке варианта, Time — сколько секунд он уже
+	// показан; Reverse — PingPong идёт назад; Done — Once дошла до конца
+	Frame   int     `json:"frame"`
+	Time    float64 `json:"time"`
+	Reverse bool    `json:"reverse,omitempty"`
+	Done    bool    `json:"done,omitempty"`
+
+	// Params — условия переходов, которые держатся, пока их не сменят
+	Params map[string]bool `json:"params,omitempty"`
+
+	// triggers — условия на один шаг, вроде нажатой атаки
+	triggers []string
+	// fresh — состояние только началось, события первого кадра ещё
+	// не отправлены
+	fresh bool
+}
+
+// New создаёт аниматор набора в начальном состоянии его машины
+func New(s *Set) Animator {
+	a := Animator{Set: s.Name, fresh: true}
+	if s.Machine != nil {
+		a.State = s.Machine.Initial
+	}
+	return a
+}
+
+// SetParam задаёт параметр переходов
+func (a *Animator) SetParam(name string, v bool) {
+	if a.Params == nil {
+		a.Params = make(map[string]bool)
+	}
+	a.Params[name] = v
+}
+
+// Fire взводит триггер: он выполняет условие переходов только на ближайшем
+// шаге и сбрасывается, даже если ни один переход его не ждал
+func (a *Animator) Fire(trigger string) {
+	a.triggers = append(a.triggers, trigger)
+}
+
+// Play переводит аниматор в состояние или, если машины нет, прямо
+// в анимацию с этим именем. Повторный Play того же состояния ничего
+// не меняет.
+func (a *Animator) Play(state string) {
+	if a.State == state {
+		return
+	}
+	a.State = state
+	a.Frame, a.Time, a.Reverse, a.Done = 0, 0, false, false
+	a.fresh = true
+}
+
+// Animation возвращает анимацию текущего состояния
+func (a *Animator) Animation(s *Set) *Animation {
+	if s.Machine != nil {
+		if st, ok := s.Machine.States[a.State]; ok {
+			return s.Animations[st.Animation]
+		}
+	}
+	return s.Animations[a.State]
+}
+
+// Locked сообщает, что текущее состояние не даёт персонажу ходить
+func (a *Animator) Locked(s *Set) bool {
+	return s.Machine != nil && s.Machine.States[a.State].Locked
+}
+
+// Current возвращает номер картинки текущего кадра и нужно ли её отразить;
+// ok ложно, если состояние не ведёт к анимации
+func (a *Animator) Current(s *Set) (index int, flipX bool, ok bool) {
+	c := a.clip(s)
+	if c == nil {
+		return 0, false, false
+	}
+	return c.Frames[a.frame(c)].Index, c.FlipX, true
+}
+
+// Update переключает состояние по переходам машины, продвигает кадры
+// на dt секунд и для каждого кадра, на который вошла анимация, вызывает
+// emit с его событиями. Длинный dt проходит все пропущенные кадры, поэтому
+// ни шаг, ни удар не теряются.
+func (a *Animator) Update(s *Set, dt float64, emit func(event string)) {
+	if s.Machine != nil {
+		if a.State == "" {
+			a.Play(s.Machine.Initial)
+		}
+		if to := s.Machine.next(a.State, a.holds); to != "" {
+			a.Play(to)
+		}
+	}
+	a.triggers = a.triggers[:0]
+
+	c := a.clip(s)
+	if c == nil {
+		return
+	}
+	a.Frame = a.frame(c)
+	if a.fresh {
+		a.fresh = false
+		a.emit(c, emit)
+	}
+	if a.Done {
+		return
+	}
+
+	mode := a.Animation(s).Mode
+	a.Time += dt
+	for a.Time >= c.Frames[a.Frame].Duration {
+		a.Time -= c.Frames[a.Frame].Duration
+		if !a.advance(mode, len(c.Frames)) {
+			a.Time = 0
+			return
+		}
+		a.emit(c, emit)
+	}
+}
+
+// holds проверяет условие перехода
+func (a *Animator) holds(cond string) bool {
+	if cond == Finished {
+		return a.Done
+	}
+	for _, t := range a.triggers {
+		if t == cond {
+			return true
+		}
+	}
+	return a.Params[cond]
+}
+
+// advance переходит на следующий кадр по режиму; ложно, если Once
+// закончилась или кадр один
+func (a *Animator) advance(mode Mode, n int) bool {
+	if n < 2 {
+		if mode == Once {
+			a.Done = true
+		}
+		return false
+	}
+	switch mode {
+	case PingPong:
+		if a.Reverse && a.Frame == 0 || !a.Reverse && a.Frame == n-1 {
+			a.Reverse = !a.Reverse
+		}
+		if a.Reverse {
+			a.Frame--
+		} else {
+			a.Frame++
+		}
+	case Once:
+		if a.Frame == n-1 {
+			a.Done = true
+			return false
+		}
+		a.Frame++
+	default:
+		a.Frame = (a.Frame + 1) % n
+	}
+	return true
+}
+
+func (a *Animator) emit(c *Clip, emit func(string)) {
+	if emit == nil {
+		return
+	}
+	for _, e := range c.Frames[a.Frame].Events {
+		emit(e)
+	}
+}
+
+func (a *Animator) clip(s *Set) *Clip {
+	anim := a.Animation(s)
+	if anim == nil {
+		return nil
+	}
+	return anim.Clip(a.Dir)
+}
+
+// frame ограничивает номер кадра длиной варианта: у направлений одной
+// анимации число кадров может различаться
+func (a *Animator) frame(c *Clip) int {
+	switch {
+	case a.Frame < 0:
+		return 0
+	case a.Frame >= len(c.Frames):
+		return len(c.Frames) - 1
+	}
+	return a.Frame
+}
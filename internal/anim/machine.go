@@ -0,0 +1,85 @@
+package anim
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Finished — условие перехода: анимация режима Once дошла до конца
+const Finished = "finished"
+
+// AnyState — From перехода, который срабатывает из любого состояния,
+// кроме того, в которое ведёт
+const AnyState = "*"
+
+// State — состояние машины: какую анимацию играть. Пока длится состояние
+// с Locked, персонаж не ходит, например во время удара.
+type State struct {
+	Animation string `json:"animation"`
+	Locked    bool   `json:"locked"`
+}
+
+// Transition — переход из From в To, когда выполнены все условия When.
+// Условие — имя параметра или триггера аниматора, "!имя" — его
+// отрицание, Finished — конец анимации.
+type Transition struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	When []string `json:"when"`
+}
+
+// Machine — машина состояний анимации. Переходы проверяются по порядку,
+// за шаг срабатывает первый подходящий.
+type Machine struct {
+	Initial     string           `json:"initial"`
+	States      map[string]State `json:"states"`
+	Transitions []Transition     `json:"transitions"`
+}
+
+// validate проверяет, что состояния и переходы ссылаются на существующее
+func (m *Machine) validate(s *Set) error {
+	if _, ok := m.States[m.Initial]; !ok {
+		return fmt.Errorf("initial state %q not found", m.Initial)
+	}
+	for name, st := range m.States {
+		if _, ok := s.Animations[st.Animation]; !ok {
+			return fmt.Errorf("state %q: animation %q not found", name, st.Animation)
+		}
+	}
+	for i, t := range m.Transitions {
+		if _, ok := m.States[t.From]; !ok && t.From != AnyState {
+			return fmt.Errorf("transition %d: state %q not found", i, t.From)
+		}
+		if _, ok := m.States[t.To]; !ok {
+			return fmt.Errorf("transition %d: state %q not found", i, t.To)
+		}
+		if len(t.When) == 0 {
+			return fmt.Errorf("transition %d from %q has no conditions", i, t.From)
+		}
+	}
+	return nil
+}
+
+// next возвращает состояние, в которое машина переходит из from, или ""
+func (m *Machine) next(from string, holds func(cond string) bool) string {
+	for _, t := range m.Transitions {
+		if t.From != from && (t.From != AnyState || t.To == from) {
+			continue
+		}
+		ok := true
+		for _, cond := range t.When {
+			if name, neg := strings.CutPrefix(cond, "!"); neg {
+				ok = !holds(name)
+			} else {
+				ok = holds(cond)
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			return t.To
+		}
+	}
+	return ""
+}
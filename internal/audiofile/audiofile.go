@@ -0,0 +1,172 @@
+// Package audiofile открывает звуковые файлы из файловой системы ресурсов:
+// определяет формат (Ogg Vorbis, WAV, MP3) по сигнатуре или расширению,
+// декодирует их потоково, не читая файл целиком в память, и пересэмплирует
+// к частоте аудиоконтекста, если частота файла другая.
+package audiofile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+// Format — формат звукового файла
+type Format int
+
+const (
+	Unknown Format = iota
+	Vorbis
+	WAV
+	MP3
+)
+
+func (f Format) String() string {
+	switch f {
+	case Vorbis:
+		return "Ogg Vorbis"
+	case WAV:
+		return "WAV"
+	case MP3:
+		return "MP3"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrUnsupported — формат файла не распознан
+var ErrUnsupported = errors.New("audiofile: unsupported format")
+
+// sniffLen — сколько байт начала файла нужно для определения формата
+const sniffLen = 12
+
+// Detect определяет формат по первым байтам файла
+func Detect(header []byte) Format {
+	switch {
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return Vorbis
+	case len(header) >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		return WAV
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return MP3
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0:
+		// Синхрослово кадра MPEG без тега ID3
+		return MP3
+	default:
+		return Unknown
+	}
+}
+
+// FormatByExt определяет формат по расширению имени файла
+func FormatByExt(name string) Format {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".ogg", ".oga":
+		return Vorbis
+	case ".wav":
+		return WAV
+	case ".mp3":
+		return MP3
+	default:
+		return Unknown
+	}
+}
+
+// decoded — поток PCM, который возвращают декодеры ebiten
+type decoded interface {
+	io.ReadSeeker
+	Length() int64
+}
+
+// Stream — декодированный поток PCM (16 бит, стерео) с частотой контекста.
+// Файл остаётся открытым, пока поток не закрыт.
+type Stream struct {
+	decoded
+	format Format
+	file   io.Closer
+}
+
+// Format возвращает формат исходного файла
+func (s *Stream) Format() Format {
+	return s.format
+}
+
+// Close закрывает исходный файл
+func (s *Stream) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// Open открывает и декодирует файл. Формат определяется по сигнатуре, а если
+// она не распознана — по расширению. Файл, который нельзя перематывать,
+// читается в память целиком.
+func Open(fsys fs.FS, name string, sampleRate int) (*Stream, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	src, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		src, f = bytes.NewReader(data), nil
+	}
+
+	s, err := decode(src, name, sampleRate)
+	if err != nil {
+		if f != nil {
+			f.Close()
+		}
+		return nil, fmt.Errorf("audiofile: %s: %w", name, err)
+	}
+	if f != nil {
+		s.file = f
+	}
+	return s, nil
+}
+
+func decode(src io.ReadSeeker, name string, sampleRate int) (*Stream, error) {
+	header := make([]byte, sniffLen)
+	n, err := io.ReadFull(src, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	format := Detect(header[:n])
+	if format == Unknown {
+		format = FormatByExt(name)
+	}
+
+	var d decoded
+	switch format {
+	case Vorbis:
+		d, err = vorbis.DecodeWithSampleRate(sampleRate, src)
+	case WAV:
+		d, err = wav.DecodeWithSampleRate(sampleRate, src)
+	case MP3:
+		d, err = mp3.DecodeWithSampleRate(sampleRate, src)
+	default:
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", format, err)
+	}
+	return &Stream{decoded: d, format: format}, nil
+}
@@ -0,0 +1,288 @@
+// Package camera — камеры мира: перевод между координатами мира и экрана,
+// плавное следование за целью с мёртвой зоной и упреждением, удержание
+// в границах карты, масштаб и тряска экрана. Каждая камера выводит мир
+// в свой прямоугольник экрана, поэтому несколько камер дают разделённый
+// экран или миникарту. Преобразование отдаётся как ebiten.GeoM, и код,
+// рисующий в координатах мира, применяет его одинаково для любой камеры.
+package camera
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// DefaultZoomLevels — ступени масштаба для ZoomStep
+var DefaultZoomLevels = []float64{0.5, 0.75, 1, 1.5, 2}
+
+// Значения по умолчанию для New
+const (
+	DefaultFollowSpeed  = 6.0
+	DefaultZoomSpeed    = 10.0
+	DefaultLookAhead    = 0.25
+	DefaultMaxLookAhead = 96.0
+)
+
+// Camera — окно в мир
+type Camera struct {
+	// X, Y — точка мира в центре окна без учёта тряски
+	X, Y float64
+
+	// Zoom — текущий масштаб: сколько пикселей экрана на пиксель мира.
+	// SetZoom и ZoomStep меняют его плавно.
+	Zoom       float64
+	ZoomLevels []float64
+	ZoomSpeed  float64
+
+	// Viewport — прямоугольник экрана, в который выводит камера
+	Viewport image.Rectangle
+
+	// Bounds — часть мира, за пределы которой камера не заглядывает;
+	// пустой прямоугольник снимает ограничение. Если мир меньше окна,
+	// камера встаёт в его центр.
+	Bounds image.Rectangle
+
+	// DeadZoneW, DeadZoneH — размер прямоугольника в центре окна (в пикселях
+	// мира), внутри которого цель двигается, не сдвигая камеру
+	DeadZoneW, DeadZoneH float64
+
+	// FollowSpeed — скорость догоняния: за секунду камера проходит
+	// 1-exp(-FollowSpeed) оставшегося пути; 0 — камера прыгает сразу
+	FollowSpeed float64
+
+	// LookAhead — на сколько секунд движения цели камера смотрит вперёд;
+	// упреждение не длиннее MaxLookAhead пикселей мира
+	LookAhead    float64
+	MaxLookAhead float64
+
+	Shake Shake
+
+	zoomTarget float64
+
+	following      bool
+	targetX        float64
+	targetY        float64
+	targetVX       float64
+	targetVY       float64
+	leadX, leadY   float64
+	shakeX, shakeY float64
+	shakeAngle     float64
+}
+
+// New создаёт камеру с окном viewport и настройками по умолчанию
+func New(viewport image.Rectangle) *Camera {
+	return &Camera{
+		Zoom:         1,
+		ZoomLevels:   DefaultZoomLevels,
+		ZoomSpeed:    DefaultZoomSpeed,
+		Viewport:     viewport,
+		FollowSpeed:  DefaultFollowSpeed,
+		LookAhead:    DefaultLookAhead,
+		MaxLookAhead: DefaultMaxLookAhead,
+		Shake:        DefaultShake(),
+		zoomTarget:   1,
+	}
+}
+
+// Follow задаёт цель на этот тик: её положение и скорость в пикселях мира
+// в секунду. Камера догоняет её в Update.
+func (c *Camera) Follow(x, y, vx, vy float64) {
+	c.following = true
+	c.targetX, c.targetY = x, y
+	c.targetVX, c.targetVY = vx, vy
+}
+
+// Unfollow перестаёт следовать за целью; камера остаётся на месте
+func (c *Camera) Unfollow() {
+	c.following = false
+	c.leadX, c.leadY = 0, 0
+}
+
+// Snap ставит камеру на точку сразу, без догоняния и упреждения, и доводит
+// масштаб до целевого: для появления на карте и загрузки игры
+func (c *Camera) Snap(x, y float64) {
+	c.X, c.Y = x, y
+	c.targetX, c.targetY = x, y
+	c.targetVX, c.targetVY = 0, 0
+	c.leadX, c.leadY = 0, 0
+	c.Zoom = c.zoomTarget
+	c.clamp()
+}
+
+// SetZoom задаёт целевой масштаб
+func (c *Camera) SetZoom(zoom float64) {
+	if zoom > 0 {
+		c.zoomTarget = zoom
+	}
+}
+
+// ZoomStep переходит на соседнюю ступень ZoomLevels: +1 — крупнее, -1 — мельче
+func (c *Camera) ZoomStep(delta int) {
+	if len(c.ZoomLevels) == 0 {
+		return
+	}
+	// Ближайшая к текущей цели ступень
+	cur := 0
+	for i, z := range c.ZoomLevels {
+		if math.Abs(z-c.zoomTarget) < math.Abs(c.ZoomLevels[cur]-c.zoomTarget) {
+			cur = i
+		}
+	}
+	next := cur + delta
+	if next < 0 {
+		next = 0
+	}
+	if next >= len(c.ZoomLevels) {
+		next = len(c.ZoomLevels) - 1
+	}
+	c.zoomTarget = c.ZoomLevels[next]
+}
+
+// ZoomTarget возвращает масштаб, к которому движется камера
+func (c *Camera) ZoomTarget() float64 {
+	return c.zoomTarget
+}
+
+// Update продвигает камеру на dt секунд: масштаб, следование, границы и тряску
+func (c *Camera) Update(dt float64) {
+	if c.Zoom != c.zoomTarget {
+		// Масштаб сближается в логарифмах, чтобы приближение и отдаление
+		// ощущались одинаково быстрыми
+		k := approach(c.ZoomSpeed, dt)
+		c.Zoom = math.Exp(math.Log(c.Zoom) + (math.Log(c.zoomTarget)-math.Log(c.Zoom))*k)
+		if math.Abs(c.Zoom-c.zoomTarget) < 1e-3 {
+			c.Zoom = c.zoomTarget
+		}
+	}
+
+	if c.following {
+		c.follow(dt)
+	}
+	c.clamp()
+
+	c.shakeX, c.shakeY, c.shakeAngle = c.Shake.update(dt)
+}
+
+// follow сдвигает камеру к цели с упреждением, если та вышла из мёртвой зоны
+func (c *Camera) follow(dt float64) {
+	k := approach(c.FollowSpeed, dt)
+
+	// Упреждение тоже сглаживается, иначе смена направления дёргает камеру
+	lx, ly := c.targetVX*c.LookAhead, c.targetVY*c.LookAhead
+	if l := math.Hypot(lx, ly); l > c.MaxLookAhead && l > 0 {
+		lx, ly = lx*c.MaxLookAhead/l, ly*c.MaxLookAhead/l
+	}
+	c.leadX += (lx - c.leadX) * k
+	c.leadY += (ly - c.leadY) * k
+
+	c.X += (deadZone(c.targetX+c.leadX-c.X, c.DeadZoneW/2)) * k
+	c.Y += (deadZone(c.targetY+c.leadY-c.Y, c.DeadZoneH/2)) * k
+}
+
+// deadZone возвращает, насколько смещение d выходит за половину зоны half
+func deadZone(d, half float64) float64 {
+	switch {
+	case d > half:
+		return d - half
+	case d < -half:
+		return d + half
+	}
+	return 0
+}
+
+// approach — доля оставшегося пути, проходимая за dt при скорости speed
+func approach(speed, dt float64) float64 {
+	if speed <= 0 {
+		return 1
+	}
+	return 1 - math.Exp(-speed*dt)
+}
+
+// clamp удерживает окно камеры в Bounds
+func (c *Camera) clamp() {
+	if c.Bounds.Empty() {
+		return
+	}
+	halfW := float64(c.Viewport.Dx()) / c.Zoom / 2
+	halfH := float64(c.Viewport.Dy()) / c.Zoom / 2
+	c.X = clampAxis(c.X, halfW, float64(c.Bounds.Min.X), float64(c.Bounds.Max.X))
+	c.Y = clampAxis(c.Y, halfH, float64(c.Bounds.Min.Y), float64(c.Bounds.Max.Y))
+}
+
+func clampAxis(v, half, min, max float64) float64 {
+	if max-min <= 2*half {
+		return (min + max) / 2
+	}
+	return math.Max(min+half, math.Min(max-half, v))
+}
+
+// GeoM возвращает преобразование из координат мира в координаты экрана
+// с учётом масштаба, тряски и положения окна. Сдвиг округляется до целого
+// пикселя экрана, чтобы тайлы не дрожали при медленном движении.
+func (c *Camera) GeoM() ebiten.GeoM {
+	x, y := c.X+c.shakeX, c.Y+c.shakeY
+
+	var m ebiten.GeoM
+	m.Translate(-math.Round(x*c.Zoom)/c.Zoom, -math.Round(y*c.Zoom)/c.Zoom)
+	m.Rotate(c.shakeAngle)
+	m.Scale(c.Zoom, c.Zoom)
+	m.Translate(float64(c.Viewport.Min.X+c.Viewport.Dx()/2), float64(c.Viewport.Min.Y+c.Viewport.Dy()/2))
+	return m
+}
+
+// WorldToScreen переводит точку мира в точку экрана
+func (c *Camera) WorldToScreen(x, y float64) (float64, float64) {
+	m := c.GeoM()
+	return m.Apply(x, y)
+}
+
+// ScreenToWorld переводит точку экрана (например, курсор) в точку мира
+func (c *Camera) ScreenToWorld(x, y float64) (float64, float64) {
+	m := c.GeoM()
+	m.Invert()
+	return m.Apply(x, y)
+}
+
+// View возвращает часть мира, попадающую в окно камеры; при повороте
+// от тряски — охватывающий её прямоугольник
+func (c *Camera) View() image.Rectangle {
+	m := c.GeoM()
+	m.Invert()
+
+	v := c.Viewport
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [4]image.Point{v.Min, {v.Max.X, v.Min.Y}, {v.Min.X, v.Max.Y}, v.Max} {
+		x, y := m.Apply(float64(p.X), float64(p.Y))
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
+
+// Target возвращает часть экрана под окном камеры; рисование в неё
+// обрезается по окну, а координаты остаются экранными
+func (c *Camera) Target(screen *ebiten.Image) *ebiten.Image {
+	return screen.SubImage(c.Viewport).(*ebiten.Image)
+}
+
+// Split делит область экрана на n окон для разделённого экрана: два — рядом,
+// три и четыре — сеткой два на два, и так далее по строкам
+func Split(area image.Rectangle, n int) []image.Rectangle {
+	if n <= 0 {
+		return nil
+	}
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := (n + cols - 1) / cols
+
+	out := make([]image.Rectangle, 0, n)
+	for i := 0; i < n; i++ {
+		col, row := i%cols, i/cols
+		out = append(out, image.Rect(
+			area.Min.X+area.Dx()*col/cols, area.Min.Y+area.Dy()*row/rows,
+			area.Min.X+area.Dx()*(col+1)/cols, area.Min.Y+area.Dy()*(row+1)/rows,
+		))
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package camera
+
+import "math"
+
+// Shake — тряска экрана от травмы: удары и взрывы добавляют травму (0..1),
+// она линейно спадает, а сила тряски растёт как её квадрат, поэтому слабые
+// толчки почти незаметны, а сильные затухают резко. Смещение и поворот
+// берутся из гладкого шума, а не из случайных чисел, чтобы экран качался,
+// а не мерцал.
+type Shake struct {
+	Trauma float64
+
+	// Decay — сколько травмы уходит за секунду
+	Decay float64
+	// MaxOffset — наибольшее смещение в пикселях мира, MaxAngle — поворот в радианах
+	MaxOffset float64
+	MaxAngle  float64
+	// Frequency — сколько раз в секунду меняется направление толчка
+	Frequency float64
+
+	// Scale — общий множитель силы тряски; 0 отключает её
+	Scale float64
+
+	time float64
+}
+
+// DefaultShake возвращает настройки тряски по умолчанию
+func DefaultShake() Shake {
+	return Shake{
+		Decay:     1.2,
+		MaxOffset: 16,
+		MaxAngle:  0.04,
+		Frequency: 18,
+		Scale:     1,
+	}
+}
+
+// Add добавляет травму; сумма не превышает 1
+func (s *Shake) Add(trauma float64) {
+	s.Trauma = math.Min(1, s.Trauma+trauma)
+}
+
+// update спадает травму на dt секунд и возвращает смещение и поворот
+func (s *Shake) update(dt float64) (float64, float64, float64) {
+	if s.Trauma <= 0 {
+		s.time = 0
+		return 0, 0, 0
+	}
+	s.time += dt
+	s.Trauma = math.Max(0, s.Trauma-s.Decay*dt)
+
+	k := s.Trauma * s.Trauma * s.Scale
+	t := s.time * s.Frequency
+	return s.MaxOffset * k * noise(1, t), s.MaxOffset * k * noise(2, t), s.MaxAngle * k * noise(3, t)
+}
+
+// noise — гладкий одномерный шум в [-1, 1]: значения в целых точках берутся
+// из хеша, между ними плавно интерполируются
+func noise(seed uint64, t float64) float64 {
+	i := math.Floor(t)
+	f := t - i
+	a, b := hash(seed, int64(i)), hash(seed, int64(i)+1)
+	return a + (b-a)*f*f*(3-2*f)
+}
+
+// hash отображает целое в [-1, 1] (финализатор splitmix64)
+func hash(seed uint64, i int64) float64 {
+	h := uint64(i)*0x9E3779B97F4A7C15 ^ seed*0xBF58476D1CE4E5B9
+	h ^= h >> 31
+	h *= 0x94D049BB133111EB
+	h ^= h >> 29
+	return float64(h>>11)/float64(1<<53)*2 - 1
+}
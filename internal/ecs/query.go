@@ -0,0 +1,28 @@
+package ecs
+
+// Each2 обходит сущности, у которых есть компоненты обоих хранилищ.
+// Обход идёт в порядке первого хранилища, поэтому первым выгодно ставить
+// самое маленькое.
+func Each2[A, B any](a *Store[A], b *Store[B], fn func(e Entity, a *A, b *B)) {
+	for i := 0; i < len(a.dense); i++ {
+		e := a.ents[i]
+		if vb := b.Get(e); vb != nil {
+			fn(e, &a.dense[i], vb)
+		}
+	}
+}
+
+// Each3 обходит сущности, у которых есть компоненты всех трёх хранилищ,
+// в порядке первого
+func Each3[A, B, C any](a *Store[A], b *Store[B], c *Store[C], fn func(e Entity, a *A, b *B, c *C)) {
+	for i := 0; i < len(a.dense); i++ {
+		e := a.ents[i]
+		vb := b.Get(e)
+		if vb == nil {
+			continue
+		}
+		if vc := c.Get(e); vc != nil {
+			fn(e, &a.dense[i], vb, vc)
+		}
+	}
+}
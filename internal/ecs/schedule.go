@@ -0,0 +1,70 @@
+package ecs
+
+// Phase — фаза кадра; системы фазы работают после всех систем предыдущей
+type Phase int
+
+const (
+	PhaseInput Phase = iota
+	PhaseAI
+	PhasePhysics
+	PhaseAnimation
+	PhaseRender
+
+	phaseCount
+)
+
+var phaseNames = [phaseCount]string{"input", "ai", "physics", "animation", "render"}
+
+func (p Phase) String() string {
+	if p < 0 || p >= phaseCount {
+		return "unknown"
+	}
+	return phaseNames[p]
+}
+
+// System — поведение над компонентами мира; dt — длина шага в секундах
+// (в фазе отрисовки 0)
+type System struct {
+	Name  string
+	Phase Phase
+	Run   func(w *World, dt float64)
+}
+
+// Scheduler — системы по фазам; внутри фазы — в порядке добавления
+type Scheduler struct {
+	phases [phaseCount][]System
+}
+
+// Add добавляет систему в конец фазы
+func (s *Scheduler) Add(phase Phase, name string, run func(w *World, dt float64)) {
+	s.phases[phase] = append(s.phases[phase], System{Name: name, Phase: phase, Run: run})
+}
+
+// Update делает шаг симуляции: фазы от ввода до анимации. После каждой
+// системы уничтоженные ею сущности удаляются.
+func (s *Scheduler) Update(w *World, dt float64) {
+	for p := PhaseInput; p < PhaseRender; p++ {
+		s.run(w, p, dt)
+	}
+}
+
+// Render вызывает системы отрисовки; цель отрисовки они берут из ресурсов мира
+func (s *Scheduler) Render(w *World) {
+	s.run(w, PhaseRender, 0)
+}
+
+func (s *Scheduler) run(w *World, p Phase, dt float64) {
+	for _, sys := range s.phases[p] {
+		sys.Run(w, dt)
+		w.Flush()
+	}
+}
+
+// Systems возвращает системы в порядке вызова
+func (s *Scheduler) Systems() []System {
+	var out []System
+	for _, list := range s.phases {
+		out = append(out, list...)
+	}
+	return out
+}
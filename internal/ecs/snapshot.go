@@ -0,0 +1,84 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Snapshot — сохраняемое состояние мира: сущности и их именованные
+// компоненты в JSON
+type Snapshot struct {
+	// Last — последний выданный номер, чтобы после загрузки номера
+	// продолжили расти
+	Last     Entity       `json:"last"`
+	Entities []EntityData `json:"entities"`
+}
+
+// EntityData — сущность в снимке: компоненты по именам хранилищ
+type EntityData struct {
+	ID         Entity                     `json:"id"`
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+// Snapshot снимает состояние мира. Временные компоненты (без имени)
+// не сохраняются; сущности идут по возрастанию номеров.
+func (w *World) Snapshot() (*Snapshot, error) {
+	snap := &Snapshot{Last: w.last}
+	for _, e := range w.Entities() {
+		data := EntityData{ID: e, Components: make(map[string]json.RawMessage)}
+		for _, s := range w.stores {
+			if s.tag() == "" || !s.has(e) {
+				continue
+			}
+			raw, err := s.encode(e)
+			if err != nil {
+				return nil, fmt.Errorf("ecs: entity %d: component %q: %w", e, s.tag(), err)
+			}
+			data.Components[s.tag()] = raw
+		}
+		snap.Entities = append(snap.Entities, data)
+	}
+	return snap, nil
+}
+
+// Restore заменяет все сущности мира сущностями снимка с теми же номерами.
+// Компоненты с незарегистрированными именами (например, от удалённого мода)
+// пропускаются и возвращаются списком; ошибка разбора компонента прерывает
+// загрузку и оставляет мир пустым.
+func (w *World) Restore(snap *Snapshot) (skipped []string, err error) {
+	w.Clear()
+	if snap == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, data := range snap.Entities {
+		if data.ID == None || w.Alive(data.ID) {
+			w.Clear()
+			return nil, fmt.Errorf("ecs: invalid or duplicate entity %d", data.ID)
+		}
+		w.alive[data.ID] = struct{}{}
+		if data.ID > w.last {
+			w.last = data.ID
+		}
+
+		for name, raw := range data.Components {
+			s, ok := w.byName[name]
+			if !ok {
+				if !seen[name] {
+					seen[name] = true
+					skipped = append(skipped, name)
+				}
+				continue
+			}
+			if err := s.decode(data.ID, raw); err != nil {
+				w.Clear()
+				return nil, fmt.Errorf("ecs: entity %d: component %q: %w", data.ID, name, err)
+			}
+		}
+	}
+	if snap.Last > w.last {
+		w.last = snap.Last
+	}
+	return skipped, nil
+}
@@ -0,0 +1,147 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// store — хранилище без типа, для операций над всеми компонентами сущности
+type store interface {
+	tag() string
+	has(e Entity) bool
+	remove(e Entity)
+	clear()
+	encode(e Entity) (json.RawMessage, error)
+	decode(e Entity, raw json.RawMessage) error
+}
+
+// Store — хранилище компонентов одного типа. Компоненты лежат подряд
+// в плотном срезе, а индекс сущности ведёт к её компоненту, поэтому обход
+// идёт по памяти без пропусков, а удаление переносит последний компонент
+// на место удалённого.
+type Store[T any] struct {
+	w     *World
+	name  string
+	dense []T
+	ents  []Entity
+	index map[Entity]int
+}
+
+// Register создаёт хранилище компонентов типа T. Компоненты с непустым
+// именем сохраняются в снимок мира под этим именем и должны переживать
+// JSON; пустое имя — временный компонент, который в снимок не попадает.
+// Повторная регистрация типа или имени — ошибка программы.
+func Register[T any](w *World, name string) *Store[T] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if _, ok := w.byType[t]; ok {
+		panic(fmt.Sprintf("ecs: component %v registered twice", t))
+	}
+	if _, ok := w.byName[name]; ok && name != "" {
+		panic(fmt.Sprintf("ecs: component name %q registered twice", name))
+	}
+
+	s := &Store[T]{w: w, name: name, index: make(map[Entity]int)}
+	w.stores = append(w.stores, s)
+	w.byType[t] = s
+	if name != "" {
+		w.byName[name] = s
+	}
+	return s
+}
+
+// StoreOf возвращает хранилище компонентов типа T или nil, если тип
+// не зарегистрирован
+func StoreOf[T any](w *World) *Store[T] {
+	s, _ := w.byType[reflect.TypeOf((*T)(nil)).Elem()].(*Store[T])
+	return s
+}
+
+// Add даёт сущности компонент или заменяет имеющийся и возвращает указатель
+// на него. Указатель годен до следующего изменения хранилища. Для
+// несуществующей сущности возвращает nil.
+func (s *Store[T]) Add(e Entity, v T) *T {
+	if !s.w.Alive(e) {
+		return nil
+	}
+	if i, ok := s.index[e]; ok {
+		s.dense[i] = v
+		return &s.dense[i]
+	}
+	s.index[e] = len(s.dense)
+	s.dense = append(s.dense, v)
+	s.ents = append(s.ents, e)
+	return &s.dense[len(s.dense)-1]
+}
+
+// Get возвращает компонент сущности или nil
+func (s *Store[T]) Get(e Entity) *T {
+	if i, ok := s.index[e]; ok {
+		return &s.dense[i]
+	}
+	return nil
+}
+
+// Has сообщает, что у сущности есть компонент
+func (s *Store[T]) Has(e Entity) bool {
+	_, ok := s.index[e]
+	return ok
+}
+
+// Remove забирает компонент у сущности. Во время обхода этого же
+// хранилища вызывать нельзя — для этого есть World.Destroy.
+func (s *Store[T]) Remove(e Entity) {
+	i, ok := s.index[e]
+	if !ok {
+		return
+	}
+	last := len(s.dense) - 1
+	s.dense[i], s.ents[i] = s.dense[last], s.ents[last]
+	s.index[s.ents[i]] = i
+	var zero T
+	s.dense[last] = zero
+	s.dense, s.ents = s.dense[:last], s.ents[:last]
+	delete(s.index, e)
+}
+
+// Len возвращает число компонентов
+func (s *Store[T]) Len() int {
+	return len(s.dense)
+}
+
+// First возвращает первую сущность с компонентом; удобно для одиночек
+// вроде героя
+func (s *Store[T]) First() (Entity, *T) {
+	if len(s.dense) == 0 {
+		return None, nil
+	}
+	return s.ents[0], &s.dense[0]
+}
+
+// Each обходит компоненты в порядке хранения
+func (s *Store[T]) Each(fn func(e Entity, v *T)) {
+	for i := 0; i < len(s.dense); i++ {
+		fn(s.ents[i], &s.dense[i])
+	}
+}
+
+func (s *Store[T]) tag() string       { return s.name }
+func (s *Store[T]) has(e Entity) bool { return s.Has(e) }
+func (s *Store[T]) remove(e Entity)   { s.Remove(e) }
+func (s *Store[T]) clear() {
+	s.dense, s.ents = s.dense[:0], s.ents[:0]
+	s.index = make(map[Entity]int)
+}
+
+func (s *Store[T]) encode(e Entity) (json.RawMessage, error) {
+	return json.Marshal(s.Get(e))
+}
+
+func (s *Store[T]) decode(e Entity, raw json.RawMessage) error {
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return err
+	}
+	s.Add(e, v)
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package ecs — реестр сущностей и компонентов для объектов мира: героя,
+// жителей, предметов и чудовищ. Сущность — просто номер; данные лежат
+// в типизированных хранилищах компонентов, по одному на тип, а поведение —
+// в системах, которые планировщик вызывает по фазам: ввод, ИИ, физика,
+// анимация, отрисовка. Компоненты с именем сохраняются в снимок мира
+// и попадают в сохранение игры.
+//
+// Пакет не зависит от ebiten: системы всех фаз, кроме отрисовки, можно
+// гонять без окна, например в тестах или на сервере.
+package ecs
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Entity — номер сущности. Номера не переиспользуются, поэтому ссылка
+// на уничтоженную сущность не начнёт указывать на новую; 0 — нет сущности.
+type Entity uint32
+
+// None — отсутствующая сущность
+const None Entity = 0
+
+// World — сущности, хранилища их компонентов и общие ресурсы систем
+type World struct {
+	last  Entity
+	alive map[Entity]struct{}
+
+	stores []store
+	byName map[string]store
+	byType map[reflect.Type]store
+
+	// doomed — сущности, уничтоженные во время работы систем; удаляются
+	// в Flush, чтобы не ломать идущий обход хранилищ
+	doomed []Entity
+
+	resources map[reflect.Type]interface{}
+}
+
+// NewWorld создаёт пустой мир
+func NewWorld() *World {
+	return &World{
+		alive:     make(map[Entity]struct{}),
+		byName:    make(map[string]store),
+		byType:    make(map[reflect.Type]store),
+		resources: make(map[reflect.Type]interface{}),
+	}
+}
+
+// Spawn создаёт сущность без компонентов
+func (w *World) Spawn() Entity {
+	w.last++
+	w.alive[w.last] = struct{}{}
+	return w.last
+}
+
+// Alive сообщает, что сущность существует и не уничтожена
+func (w *World) Alive(e Entity) bool {
+	_, ok := w.alive[e]
+	return ok
+}
+
+// Destroy помечает сущность к удалению; она исчезает вместе с компонентами
+// в ближайшем Flush. До него Alive ещё истинно.
+func (w *World) Destroy(e Entity) {
+	if w.Alive(e) {
+		w.doomed = append(w.doomed, e)
+	}
+}
+
+// Flush удаляет сущности, помеченные Destroy. Планировщик вызывает его
+// после каждой системы.
+func (w *World) Flush() {
+	for _, e := range w.doomed {
+		if !w.Alive(e) {
+			continue
+		}
+		for _, s := range w.stores {
+			s.remove(e)
+		}
+		delete(w.alive, e)
+	}
+	w.doomed = w.doomed[:0]
+}
+
+// Len возвращает число живых сущностей
+func (w *World) Len() int {
+	return len(w.alive)
+}
+
+// Entities возвращает живые сущности по возрастанию номеров
+func (w *World) Entities() []Entity {
+	out := make([]Entity, 0, len(w.alive))
+	for e := range w.alive {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// Clear удаляет все сущности; хранилища и ресурсы остаются. Номера
+// продолжают расти, чтобы старые ссылки не ожили.
+func (w *World) Clear() {
+	for _, s := range w.stores {
+		s.clear()
+	}
+	w.alive = make(map[Entity]struct{})
+	w.doomed = w.doomed[:0]
+}
+
+// SetResource кладёт в мир общий для систем объект одного типа: ввод кадра,
+// мир столкновений, цель отрисовки
+func SetResource[T any](w *World, v *T) {
+	w.resources[reflect.TypeOf(v)] = v
+}
+
+// Resource возвращает ресурс типа T или nil
+func Resource[T any](w *World) *T {
+	v, _ := w.resources[reflect.TypeOf((*T)(nil))].(*T)
+	return v
+}
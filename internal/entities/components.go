@@ -0,0 +1,116 @@
+// Package entities — компоненты и системы объектов мира: героя, жителей,
+// табличек и всего, что появится на карте. Компоненты — данные без
+// поведения, системы — функции фаз ecs.Scheduler. Пакет не зависит от
+// ebiten; системы отрисовки живут в пакете game.
+package entities
+
+import (
+	"aethelgard/internal/anim"
+	"aethelgard/internal/ecs"
+	"aethelgard/internal/physics"
+	"aethelgard/internal/player"
+)
+
+// Position — точка у ног сущности в пикселях мира
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Collider — хитбокс неподвижной сущности относительно Position. Ходящие
+// сущности сталкиваются хитбоксом своего контроллера и в нём не нуждаются.
+type Collider struct {
+	Hitbox physics.Rect `json:"hitbox"`
+	Solid  bool         `json:"solid"`
+}
+
+// Intent — куда сущность хочет идти на этом шаге, от -1 до 1 по осям;
+// пишут ввод и ИИ, читает движение. Временный компонент, не сохраняется.
+type Intent struct {
+	X, Y float64
+}
+
+// Interactable — то, что можно прочитать или с кем можно поговорить:
+// ключ локализации надписи или реплики
+type Interactable struct {
+	Text string `json:"text"`
+}
+
+// Hero — метка героя, которым управляет игрок
+type Hero struct{}
+
+// Name — имя объекта на карте, по которому его находят сюжет и скрипты
+type Name struct {
+	Name string `json:"name"`
+}
+
+// Wander — ИИ жителя, который бродит вокруг дома: выбирает направление
+// или остановку на случайное время и возвращается, отойдя дальше Radius.
+// Состояние генератора случайных чисел хранится в компоненте, поэтому
+// после загрузки житель продолжает так же, как продолжил бы без неё.
+type Wander struct {
+	HomeX  float64 `json:"home_x"`
+	HomeY  float64 `json:"home_y"`
+	Radius float64 `json:"radius"`
+
+	Wait float64 `json:"wait"`
+	DirX float64 `json:"dir_x"`
+	DirY float64 `json:"dir_y"`
+	Seed uint64  `json:"seed"`
+}
+
+// Figure — внешний вид сущности без спрайта: цветная фигура W×H над
+// точкой у ног
+type Figure struct {
+	Color [3]uint8 `json:"color"`
+	W     float64  `json:"w"`
+	H     float64  `json:"h"`
+}
+
+// Components — хранилища компонентов мира
+type Components struct {
+	Position     *ecs.Store[Position]
+	Collider     *ecs.Store[Collider]
+	Walker       *ecs.Store[player.Controller]
+	Intent       *ecs.Store[Intent]
+	Interactable *ecs.Store[Interactable]
+	Hero         *ecs.Store[Hero]
+	Name         *ecs.Store[Name]
+	Wander       *ecs.Store[Wander]
+	Figure       *ecs.Store[Figure]
+	Animator     *ecs.Store[anim.Animator]
+}
+
+// Register регистрирует компоненты в мире. Имена хранилищ — ключи
+// компонентов в сохранении, менять их нельзя.
+func Register(w *ecs.World) *Components {
+	return &Components{
+		Position:     ecs.Register[Position](w, "position"),
+		Collider:     ecs.Register[Collider](w, "collider"),
+		Walker:       ecs.Register[player.Controller](w, "walker"),
+		Intent:       ecs.Register[Intent](w, ""),
+		Interactable: ecs.Register[Interactable](w, "interactable"),
+		Hero:         ecs.Register[Hero](w, "hero"),
+		Name:         ecs.Register[Name](w, "name"),
+		Wander:       ecs.Register[Wander](w, "wander"),
+		Figure:       ecs.Register[Figure](w, "figure"),
+		Animator:     ecs.Register[anim.Animator](w, "animator"),
+	}
+}
+
+// FindHero возвращает героя или ecs.None
+func (c *Components) FindHero() ecs.Entity {
+	e, _ := c.Hero.First()
+	return e
+}
+
+// Find возвращает сущность с именем или ecs.None
+func (c *Components) Find(name string) ecs.Entity {
+	found := ecs.None
+	c.Name.Each(func(e ecs.Entity, n *Name) {
+		if found == ecs.None && n.Name == name {
+			found = e
+		}
+	})
+	return found
+}
@@ -0,0 +1,230 @@
+package entities
+
+import (
+	"aethelgard/internal/anim"
+	"aethelgard/internal/ecs"
+	"aethelgard/internal/physics"
+	"aethelgard/internal/player"
+	"math"
+)
+
+// Control — ввод игрока на текущий шаг, ресурс мира: направление от -1
+// до 1 по осям и нажатая атака. Без окна его заполняет тест или запись
+// ввода. Attack держится до ближайшего шага, который его заберёт.
+type Control struct {
+	X, Y   float64
+	Attack bool
+}
+
+// Параметр и триггер машин состояний анимации, которые задают системы
+const (
+	// ParamMoving — ходящий движется быстрее MovingSpeed
+	ParamMoving = "moving"
+	// TriggerAttack — герой атакует
+	TriggerAttack = "attack"
+)
+
+// MovingSpeed — скорость в пикселях в секунду, ниже которой ходящий
+// считается стоящим
+const MovingSpeed = 5.0
+
+// FrameEvents — обработчики событий кадров анимации, ресурс мира. Событие
+// приходит с сущностью, чья анимация вошла на кадр. Обработчик вызывается
+// посреди обхода аниматоров: уничтожать сущности можно (это отложено),
+// а добавлять и забирать аниматоры нельзя.
+type FrameEvents struct {
+	handlers map[string][]func(e ecs.Entity)
+}
+
+// On подписывает обработчик на событие
+func (fe *FrameEvents) On(event string, fn func(e ecs.Entity)) {
+	if fe.handlers == nil {
+		fe.handlers = make(map[string][]func(ecs.Entity))
+	}
+	fe.handlers[event] = append(fe.handlers[event], fn)
+}
+
+func (fe *FrameEvents) emit(e ecs.Entity, event string) {
+	for _, fn := range fe.handlers[event] {
+		fn(e)
+	}
+}
+
+// Пределы паузы жителя между сменами направления, в секундах
+const (
+	wanderMinWait = 0.8
+	wanderMaxWait = 2.4
+)
+
+// AddSystems добавляет системы мира в планировщик: ввод героя, ИИ жителей,
+// движение со столкновениями и анимацию. Движению нужен ресурс
+// *physics.World с тайловой сеткой карты; тела сущностей система заносит
+// в него сама. Анимации нужен ресурс *anim.Library, события кадров уходят
+// в ресурс *FrameEvents, если он есть.
+func AddSystems(s *ecs.Scheduler, c *Components) {
+	s.Add(ecs.PhaseInput, "hero_input", c.heroInput)
+	s.Add(ecs.PhaseAI, "wander", c.wander)
+	s.Add(ecs.PhasePhysics, "movement", c.movement)
+	s.Add(ecs.PhaseAnimation, "animation", c.animate)
+}
+
+// heroInput передаёт ввод игрока героям
+func (c *Components) heroInput(w *ecs.World, dt float64) {
+	ctl := ecs.Resource[Control](w)
+	c.Hero.Each(func(e ecs.Entity, _ *Hero) {
+		in := c.Intent.Get(e)
+		if in == nil {
+			in = c.Intent.Add(e, Intent{})
+		}
+		if ctl != nil {
+			in.X, in.Y = ctl.X, ctl.Y
+		} else {
+			in.X, in.Y = 0, 0
+		}
+		if a := c.Animator.Get(e); a != nil && ctl != nil && ctl.Attack {
+			a.Fire(TriggerAttack)
+		}
+	})
+	if ctl != nil {
+		ctl.Attack = false
+	}
+}
+
+// wander выбирает жителям направление
+func (c *Components) wander(w *ecs.World, dt float64) {
+	ecs.Each2(c.Wander, c.Position, func(e ecs.Entity, wd *Wander, pos *Position) {
+		wd.Wait -= dt
+		// Уходящий за радиус житель поворачивает к дому, не дожидаясь конца
+		// паузы; стоящий дожидается её
+		dx, dy := wd.HomeX-pos.X, wd.HomeY-pos.Y
+		moving := wd.DirX != 0 || wd.DirY != 0
+		away := moving && math.Hypot(dx, dy) > wd.Radius && wd.DirX*dx+wd.DirY*dy <= 0
+		if wd.Wait <= 0 || away {
+			wd.choose(pos)
+		}
+
+		in := c.Intent.Get(e)
+		if in == nil {
+			in = c.Intent.Add(e, Intent{})
+		}
+		in.X, in.Y = wd.DirX, wd.DirY
+	})
+}
+
+// choose задаёт новое направление: к дому, если житель ушёл далеко,
+// иначе случайное или остановку
+func (wd *Wander) choose(pos *Position) {
+	r := wd.next()
+	wd.Wait = wanderMinWait + float64(r%1000)/1000*(wanderMaxWait-wanderMinWait)
+
+	dx, dy := wd.HomeX-pos.X, wd.HomeY-pos.Y
+	switch {
+	case math.Hypot(dx, dy) > wd.Radius:
+		d, _ := player.DirectionOf(dx, dy)
+		wd.DirX, wd.DirY = d.Vector()
+	case r/1000%3 == 0:
+		wd.DirX, wd.DirY = 0, 0
+	default:
+		wd.DirX, wd.DirY = player.Direction(r / 3000 % 8).Vector()
+	}
+}
+
+// Stop останавливает жителя на время, например пока с ним говорят
+func (wd *Wander) Stop(seconds float64) {
+	wd.DirX, wd.DirY = 0, 0
+	wd.Wait = seconds
+}
+
+// next — генератор xorshift64*
+func (wd *Wander) next() uint64 {
+	if wd.Seed == 0 {
+		wd.Seed = 0x9E3779B97F4A7C15
+	}
+	wd.Seed ^= wd.Seed >> 12
+	wd.Seed ^= wd.Seed << 25
+	wd.Seed ^= wd.Seed >> 27
+	return wd.Seed * 0x2545F4914F6CDD1D >> 11
+}
+
+// movement заносит тела сущностей в мир столкновений и двигает ходящих
+func (c *Components) movement(w *ecs.World, dt float64) {
+	phys := ecs.Resource[physics.World](w)
+	if phys == nil {
+		return
+	}
+	index := c.SyncBodies(phys)
+	lib := ecs.Resource[anim.Library](w)
+
+	ecs.Each2(c.Walker, c.Position, func(e ecs.Entity, ctl *player.Controller, pos *Position) {
+		var ix, iy float64
+		if in := c.Intent.Get(e); in != nil {
+			ix, iy = in.X, in.Y
+		}
+		// Во время удара и прочих запирающих состояний персонаж тормозит
+		if a := c.Animator.Get(e); a != nil && lib != nil {
+			if set := (*lib)[a.Set]; set != nil && a.Locked(set) {
+				ix, iy = 0, 0
+			}
+		}
+		ctl.X, ctl.Y = pos.X, pos.Y
+		ctl.BodyID = int(e)
+		ctl.Step(ix, iy, dt, phys)
+		pos.X, pos.Y = ctl.X, ctl.Y
+
+		// Следующие ходящие сталкиваются уже с новым положением
+		if i, ok := index[e]; ok {
+			phys.Bodies[i].Rect = ctl.Box()
+		}
+	})
+}
+
+// animate передаёт аниматорам движение и взгляд ходящих и продвигает
+// анимации; события кадров уходят обработчикам FrameEvents
+func (c *Components) animate(w *ecs.World, dt float64) {
+	lib := ecs.Resource[anim.Library](w)
+	if lib == nil {
+		return
+	}
+	events := ecs.Resource[FrameEvents](w)
+
+	c.Animator.Each(func(e ecs.Entity, a *anim.Animator) {
+		set := (*lib)[a.Set]
+		if set == nil {
+			return
+		}
+		if ctl := c.Walker.Get(e); ctl != nil {
+			a.Dir = ctl.Facing.String()
+			a.SetParam(ParamMoving, math.Hypot(ctl.VX, ctl.VY) > MovingSpeed)
+		}
+		var emit func(string)
+		if events != nil {
+			emit = func(event string) { events.emit(e, event) }
+		}
+		a.Update(set, dt, emit)
+	})
+}
+
+// SyncBodies заменяет тела мира столкновений телами сущностей: хитбоксами
+// неподвижных сущностей и контроллеров ходящих. Номер тела — номер
+// сущности. Возвращает индекс тела каждой сущности в phys.Bodies.
+func (c *Components) SyncBodies(phys *physics.World) map[ecs.Entity]int {
+	index := make(map[ecs.Entity]int, c.Collider.Len()+c.Walker.Len())
+	phys.Bodies = phys.Bodies[:0]
+	ecs.Each2(c.Collider, c.Position, func(e ecs.Entity, col *Collider, pos *Position) {
+		index[e] = len(phys.Bodies)
+		phys.Bodies = append(phys.Bodies, physics.Body{
+			ID:    int(e),
+			Rect:  col.Hitbox.Translate(pos.X, pos.Y),
+			Solid: col.Solid,
+		})
+	})
+	ecs.Each2(c.Walker, c.Position, func(e ecs.Entity, ctl *player.Controller, pos *Position) {
+		index[e] = len(phys.Bodies)
+		phys.Bodies = append(phys.Bodies, physics.Body{
+			ID:    int(e),
+			Rect:  ctl.Hitbox.Translate(pos.X, pos.Y),
+			Solid: true,
+		})
+	})
+	return index
+}
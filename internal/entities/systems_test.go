@@ -0,0 +1,230 @@
+package entities
+
+import (
+	"aethelgard/internal/anim"
+	"aethelgard/internal/ecs"
+	"aethelgard/internal/physics"
+	"aethelgard/internal/player"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// box — комната 20×20 тайлов по 16 пикселей, обнесённая стеной
+type box struct{}
+
+func (box) Solid(tx, ty int) bool {
+	return tx <= 0 || ty <= 0 || tx >= 19 || ty >= 19
+}
+
+// walkerSet — набор анимаций, как у героя: стоит, идёт и бьёт с шагами
+// и ударом на кадрах
+const walkerSet = `{
+	"animations": {
+		"idle": {"frames": [0, 1], "duration": 500},
+		"walk": {"frames": [2, 3, 4, 5], "duration": 100, "events": {"1": ["footstep"], "3": ["footstep"]}},
+		"attack": {"mode": "once", "frames": [6, 7, 8], "duration": 80, "events": {"1": ["hit"]}}
+	},
+	"machine": {
+		"initial": "idle",
+		"states": {
+			"idle": {"animation": "idle"},
+			"walk": {"animation": "walk"},
+			"attack": {"animation": "attack", "locked": true}
+		},
+		"transitions": [
+			{"from": "*", "to": "attack", "when": ["attack"]},
+			{"from": "idle", "to": "walk", "when": ["moving"]},
+			{"from": "walk", "to": "idle", "when": ["!moving"]},
+			{"from": "attack", "to": "idle", "when": ["finished"]}
+		]
+	}
+}`
+
+const step = 1.0 / 60
+
+// newWorld собирает мир без окна: компоненты, системы и ресурсы, которые
+// им нужны
+func newWorld(t *testing.T) (*ecs.World, *Components, *ecs.Scheduler, map[string]int) {
+	t.Helper()
+	set, err := anim.Parse("walker", []byte(walkerSet), 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := ecs.NewWorld()
+	c := Register(w)
+	s := &ecs.Scheduler{}
+	AddSystems(s, c)
+
+	lib := anim.Library{"walker": set}
+	ecs.SetResource(w, &lib)
+	ecs.SetResource(w, &physics.World{Grid: box{}, TileW: 16, TileH: 16, CornerSlop: 8})
+	ecs.SetResource(w, &Control{})
+
+	events := map[string]int{}
+	fe := &FrameEvents{}
+	for _, name := range []string{"footstep", "hit"} {
+		name := name
+		fe.On(name, func(ecs.Entity) { events[name]++ })
+	}
+	ecs.SetResource(w, fe)
+	return w, c, s, events
+}
+
+// populate добавляет героя, жителя и табличку
+func populate(w *ecs.World, c *Components) (hero, npc ecs.Entity) {
+	set := (*ecs.Resource[anim.Library](w))["walker"]
+
+	hero = w.Spawn()
+	c.Hero.Add(hero, Hero{})
+	c.Position.Add(hero, Position{X: 80, Y: 160})
+	c.Walker.Add(hero, *player.New(80, 160))
+	c.Animator.Add(hero, anim.New(set))
+
+	npc = w.Spawn()
+	c.Position.Add(npc, Position{X: 200, Y: 200})
+	c.Walker.Add(npc, *player.New(200, 200))
+	c.Wander.Add(npc, Wander{HomeX: 200, HomeY: 200, Radius: 48, Seed: 3})
+	c.Animator.Add(npc, anim.New(set))
+	c.Name.Add(npc, Name{Name: "villager"})
+
+	sign := w.Spawn()
+	c.Position.Add(sign, Position{X: 160, Y: 120})
+	c.Collider.Add(sign, Collider{Hitbox: physics.R(-8, -16, 8, 0), Solid: true})
+	c.Interactable.Add(sign, Interactable{Text: "Sign"})
+	return hero, npc
+}
+
+func run(w *ecs.World, s *ecs.Scheduler, ticks int) {
+	for i := 0; i < ticks; i++ {
+		s.Update(w, step)
+	}
+}
+
+func snapshotJSON(t *testing.T, w *ecs.World) []byte {
+	t.Helper()
+	snap, err := w.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// TestSystemsHeadless — ввод героя, ИИ жителя, движение и анимация
+// работают без окна, а события кадров доходят до подписчиков
+func TestSystemsHeadless(t *testing.T) {
+	w, c, s, events := newWorld(t)
+	hero, _ := populate(w, c)
+	ctl := ecs.Resource[Control](w)
+
+	ctl.X = 1
+	run(w, s, 60)
+	pos, a := c.Position.Get(hero), c.Animator.Get(hero)
+	if pos.X <= 150 {
+		t.Errorf("hero walked to x=%.1f, want past 150", pos.X)
+	}
+	if a.State != "walk" || a.Dir != "right" {
+		t.Errorf("hero animator = %s %s, want walk right", a.State, a.Dir)
+	}
+	if events["footstep"] == 0 {
+		t.Error("walking produced no footstep events")
+	}
+
+	// Удар останавливает героя и бьёт ровно один раз
+	ctl.X, ctl.Attack = 0, true
+	run(w, s, 1)
+	if a := c.Animator.Get(hero); a.State != "attack" {
+		t.Fatalf("hero state after attack = %s, want attack", a.State)
+	}
+	ctl.X = 1
+	run(w, s, 10)
+	if vx := c.Walker.Get(hero).VX; vx > 0 {
+		t.Errorf("hero walks during attack: vx=%.1f", vx)
+	}
+	run(w, s, 30)
+	if events["hit"] != 1 {
+		t.Errorf("hit events = %d, want 1", events["hit"])
+	}
+	if a := c.Animator.Get(hero); a.State == "attack" {
+		t.Error("attack never finished")
+	}
+
+	// Герой упирается в стену, а не проходит сквозь неё
+	run(w, s, 600)
+	if box := c.Walker.Get(hero).Box(); box.MaxX > 19*16 {
+		t.Errorf("hero went into the wall: %+v", box)
+	}
+}
+
+// TestSnapshotRoundTrip — мир из снимка совпадает с исходным и дальше
+// живёт так же, как жил бы без сохранения
+func TestSnapshotRoundTrip(t *testing.T) {
+	w, c, s, _ := newWorld(t)
+	hero, npc := populate(w, c)
+	ctl := ecs.Resource[Control](w)
+	ctl.X, ctl.Y = 1, 1
+	run(w, s, 45)
+
+	saved := snapshotJSON(t, w)
+
+	var snap ecs.Snapshot
+	if err := json.Unmarshal(saved, &snap); err != nil {
+		t.Fatal(err)
+	}
+	w2, c2, s2, _ := newWorld(t)
+	skipped, err := w2.Restore(&snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("skipped components: %v", skipped)
+	}
+	if got := snapshotJSON(t, w2); !bytes.Equal(got, saved) {
+		t.Errorf("restored snapshot differs:\n got %s\nwant %s", got, saved)
+	}
+	if c2.FindHero() != hero || c2.Find("villager") != npc {
+		t.Errorf("restored hero %d, villager %d; want %d, %d", c2.FindHero(), c2.Find("villager"), hero, npc)
+	}
+
+	// Обе копии продолжают одинаково
+	ctl2 := ecs.Resource[Control](w2)
+	ctl2.X, ctl2.Y = ctl.X, ctl.Y
+	run(w, s, 120)
+	run(w2, s2, 120)
+	if a, b := snapshotJSON(t, w), snapshotJSON(t, w2); !bytes.Equal(a, b) {
+		t.Errorf("worlds diverged after restore:\n got %s\nwant %s", b, a)
+	}
+
+	// Новые сущности не занимают номера из снимка
+	if e := w2.Spawn(); e != snap.Last+1 {
+		t.Errorf("new entity after restore = %d, want %d", e, snap.Last+1)
+	}
+}
+
+// TestRestoreUnknownComponent — компонент, которого нет в этой сборке,
+// пропускается, а остальное загружается
+func TestRestoreUnknownComponent(t *testing.T) {
+	w, c, _, _ := newWorld(t)
+	snap := &ecs.Snapshot{Last: 1, Entities: []ecs.EntityData{{
+		ID: 1,
+		Components: map[string]json.RawMessage{
+			"position": json.RawMessage(`{"x":1,"y":2}`),
+			"mod_aura": json.RawMessage(`{"glow":true}`),
+		},
+	}}}
+	skipped, err := w.Restore(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 1 || skipped[0] != "mod_aura" {
+		t.Errorf("skipped = %v, want [mod_aura]", skipped)
+	}
+	if p := c.Position.Get(1); p == nil || p.X != 1 || p.Y != 2 {
+		t.Errorf("position = %+v, want {1 2}", p)
+	}
+}
@@ -0,0 +1,80 @@
+package fonts
+
+import (
+	"image"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// fallbackFace — начертание, которое берёт каждый символ из первого шрифта
+// цепочки, где он есть. Метрики строки берутся из основного шрифта.
+type fallbackFace struct {
+	faces []font.Face
+	fonts []*sfnt.Font
+
+	buf    sfnt.Buffer
+	byRune map[rune]int
+}
+
+func newFallbackFace(faces []font.Face, fonts []*sfnt.Font) *fallbackFace {
+	return &fallbackFace{
+		faces:  faces,
+		fonts:  fonts,
+		byRune: make(map[rune]int),
+	}
+}
+
+// faceFor возвращает индекс начертания, в котором есть символ r; если его
+// нет нигде, используется основное начертание (с его заменяющим глифом)
+func (f *fallbackFace) faceFor(r rune) int {
+	if i, ok := f.byRune[r]; ok {
+		return i
+	}
+
+	i := 0
+	for j, sf := range f.fonts {
+		if idx, err := sf.GlyphIndex(&f.buf, r); err == nil && idx != 0 {
+			i = j
+			break
+		}
+	}
+	f.byRune[r] = i
+	return i
+}
+
+func (f *fallbackFace) Close() error {
+	var firstErr error
+	for _, face := range f.faces {
+		if err := face.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faces[f.faceFor(r)].Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faces[f.faceFor(r)].GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faces[f.faceFor(r)].GlyphAdvance(r)
+}
+
+// Kern применяется только к паре символов из одного шрифта
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	i := f.faceFor(r0)
+	if i != f.faceFor(r1) {
+		return 0
+	}
+	return f.faces[i].Kern(r0, r1)
+}
+
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
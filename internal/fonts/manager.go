@@ -0,0 +1,160 @@
+// Package fonts загружает шрифтовые файлы, кэширует начертания по
+// (семейство, кегль, DPI) и собирает цепочки запасных шрифтов для символов,
+// которых нет в основном шрифте.
+package fonts
+
+import (
+	"fmt"
+	"io/fs"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+)
+
+// DefaultDPI — DPI, под который подобраны кегли в игре
+const DefaultDPI = 72
+
+type faceKey struct {
+	family string
+	size   float64
+	dpi    float64
+}
+
+// Manager — реестр шрифтов и кэш начертаний
+type Manager struct {
+	fonts     map[string]*opentype.Font
+	fallbacks map[string][]string
+	faces     map[faceKey]font.Face
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		fonts:     make(map[string]*opentype.Font),
+		fallbacks: make(map[string][]string),
+		faces:     make(map[faceKey]font.Face),
+	}
+}
+
+// Register разбирает шрифт из байтов и регистрирует его под именем семейства
+func (m *Manager) Register(family string, data []byte) error {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return fmt.Errorf("fonts: parse %s: %w", family, err)
+	}
+	m.fonts[family] = f
+	return nil
+}
+
+// RegisterFile читает шрифтовой файл из fsys и регистрирует его
+func (m *Manager) RegisterFile(fsys fs.FS, family, path string) error {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return fmt.Errorf("fonts: load %s: %w", family, err)
+	}
+	return m.Register(family, data)
+}
+
+// Has сообщает, зарегистрировано ли семейство
+func (m *Manager) Has(family string) bool {
+	_, ok := m.fonts[family]
+	return ok
+}
+
+// SetFallbacks задаёт запасные семейства, к которым обращаться за символами,
+// отсутствующими в family. Кэш начертаний семейства сбрасывается.
+func (m *Manager) SetFallbacks(family string, fallbacks ...string) {
+	m.fallbacks[family] = append([]string(nil), fallbacks...)
+	for k, f := range m.faces {
+		if k.family == family {
+			f.Close()
+			delete(m.faces, k)
+		}
+	}
+}
+
+// chain возвращает зарегистрированные семейства цепочки: само семейство,
+// затем запасные в порядке объявления
+func (m *Manager) chain(family string) []string {
+	var out []string
+	for _, f := range append([]string{family}, m.fallbacks[family]...) {
+		if m.Has(f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Face возвращает начертание семейства с заданным кеглем и DPI. Если файл
+// семейства не загрузился, начертание строится из его запасных шрифтов;
+// ошибка возвращается, только если в цепочке нет ни одного шрифта.
+func (m *Manager) Face(family string, size, dpi float64) (font.Face, error) {
+	key := faceKey{family, size, dpi}
+	if f, ok := m.faces[key]; ok {
+		return f, nil
+	}
+
+	chain := m.chain(family)
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("fonts: no font available for family %s", family)
+	}
+
+	faces := make([]font.Face, 0, len(chain))
+	fonts := make([]*sfnt.Font, 0, len(chain))
+	for _, name := range chain {
+		f, err := opentype.NewFace(m.fonts[name], &opentype.FaceOptions{
+			Size:    size,
+			DPI:     dpi,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			for _, created := range faces {
+				created.Close()
+			}
+			return nil, fmt.Errorf("fonts: create %s %.0fpt face: %w", name, size, err)
+		}
+		faces = append(faces, f)
+		fonts = append(fonts, m.fonts[name])
+	}
+
+	var face font.Face = faces[0]
+	if len(faces) > 1 {
+		face = newFallbackFace(faces, fonts)
+	}
+	m.faces[key] = face
+	return face, nil
+}
+
+// Missing возвращает символы строки, которых нет ни в одном шрифте цепочки семейства
+func (m *Manager) Missing(family, s string) []rune {
+	var buf sfnt.Buffer
+	var missing []rune
+	seen := map[rune]bool{}
+
+	for _, r := range s {
+		if seen[r] || r == ' ' || r == '\n' {
+			continue
+		}
+		seen[r] = true
+
+		found := false
+		for _, name := range m.chain(family) {
+			if idx, err := m.fonts[name].GlyphIndex(&buf, r); err == nil && idx != 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
+// Close освобождает все созданные начертания
+func (m *Manager) Close() {
+	for k, f := range m.faces {
+		f.Close()
+		delete(m.faces, k)
+	}
+}
@@ -1,94 +1,56 @@
 package game
 
 import (
-	"bytes"
-	"log"
-	"os"
-
-	"github.com/hajimehoshi/ebiten/v2/audio"
-	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"aethelgard/internal/audiofile"
+	"aethelgard/internal/mixer"
+	"aethelgard/internal/music"
+	"io"
 )
 
-// loadAndPlayBackgroundMusic загружает и воспроизводит фоновую музыку
-func (g *Game) loadAndPlayBackgroundMusic() error {
-	log.Println("Attempting to load background music...")
+// SampleRate — частота дискретизации аудиоконтекста
+const SampleRate = 44100
 
-	// Читаем весь файл в память
-	audioData, err := os.ReadFile("assets/main_menu_sound.mp3")
-	if err != nil {
-		log.Printf("Failed to read audio file: %v", err)
-		return err
-	}
-	log.Printf("Audio file loaded, size: %d bytes", len(audioData))
+// decodeAudio открывает звуковой файл из ресурсов потоком, пересэмплированным
+// к частоте контекста; поток нужно закрыть, когда плеер больше не нужен
+func (g *Game) decodeAudio(name string) (*audiofile.Stream, error) {
+	return audiofile.Open(g.assets, name, SampleRate)
+}
 
-	// Декодируем MP3 из байтов
-	decodedStream, err := mp3.DecodeWithoutResampling(bytes.NewReader(audioData))
-	if err != nil {
-		log.Printf("Failed to decode MP3: %v", err)
-		return err
+// loadMusic создаёт проигрыватель музыки и регистрирует плейлисты сцен
+func (g *Game) loadMusic() {
+	out := func(src io.ReadSeeker) (music.Player, error) {
+		return g.audioContext.NewPlayer(src)
 	}
-	log.Printf("MP3 decoded successfully, length: %d", decodedStream.Length())
-
-	// Создаем бесконечный поток для зацикливания
-	infiniteLoop := audio.NewInfiniteLoop(decodedStream, decodedStream.Length())
-	log.Println("Infinite loop created")
-
-	// Создаем плеер
-	player, err := g.audioContext.NewPlayer(infiniteLoop)
-	if err != nil {
-		log.Printf("Failed to create player: %v", err)
-		return err
+	g.music = music.NewDirector(SampleRate, out, g.mixer, func(name string) (music.Stream, error) {
+		stream, err := g.decodeAudio(name)
+		if err != nil {
+			return nil, err
+		}
+		return stream, nil
+	})
+	g.music.SetFade(MusicCrossfade)
+	for _, p := range musicPlaylists {
+		g.music.Add(p)
 	}
-	log.Println("Player created successfully")
-
-	// Устанавливаем громкость на мастер-громкость
-	player.SetVolume(g.masterVolume)
-	log.Printf("Volume set to %.2f", g.masterVolume)
-
-	g.bgMusic = player
-
-	// Запускаем воспроизведение
-	g.bgMusic.Play()
-	log.Printf("Music playback started, IsPlaying: %v", g.bgMusic.IsPlaying())
-
-	return nil
 }
 
-// updateMusicState управляет воспроизведением музыки в зависимости от текущего состояния игры
+// updateMusicState переключает музыку на плейлист верхней сцены стека
 func (g *Game) updateMusicState() {
-	if g.bgMusic == nil {
+	if g.music == nil {
 		return
 	}
 
-	var targetVolume float64
-
-	if g.state == MenuState {
-		// В главном меню - полная громкость
-		targetVolume = g.masterVolume
-
-		if !g.bgMusic.IsPlaying() {
-			g.bgMusic.Rewind()
-			g.bgMusic.Play()
-			log.Println("Music resumed")
-		}
-	} else if g.state == SettingsState || g.state == GameState {
-		// В настройках и игре - приглушаем до 20%
-		targetVolume = g.masterVolume * 0.2
-
-		if !g.bgMusic.IsPlaying() {
-			g.bgMusic.Rewind()
-			g.bgMusic.Play()
-			log.Println("Music resumed (quiet)")
-		}
-	} else {
-		// В других состояниях - останавливаем
-		if g.bgMusic.IsPlaying() {
-			g.bgMusic.Pause()
-			log.Println("Music paused")
-		}
+	scene, ok := g.CurrentScene().(musicScene)
+	if !ok {
+		// Сцена без музыки - музыка затихает и потом продолжится с того же места
+		g.music.Stop()
 		return
 	}
 
-	// Применяем громкость
-	g.bgMusic.SetVolume(targetVolume)
-}
\ No newline at end of file
+	if name := scene.Playlist(); name != "" {
+		g.music.Play(name)
+	}
+
+	// Уровень музыки зависит от сцены; громкость шины задаёт игрок
+	g.mixer.SetLevel(mixer.Music, scene.MusicLevel())
+}
@@ -1,17 +1,35 @@
 package game
 
+// ScreenWidth, ScreenHeight — экран, под который нарисован интерфейс.
+// Логический экран подстраивается под пропорции окна (см. logicalSize),
+// а интерфейс масштабируется от этого размера.
 const (
 	ScreenWidth  = 1280
 	ScreenHeight = 720
 )
 
+// BackgroundVideos — ролики фона меню в порядке предпочтения. В ресурсах
+// лежит Motion JPEG AVI 640×360; каталог кадров video_frames в поставку не
+// входит и нужен модам и отладке, когда ролик удобнее заменить набором
+// картинок.
+var BackgroundVideos = []string{
+	"menu-background-video.avi",
+	"video_frames",
+}
+
+// BackgroundVideoFPS — частота кадров ролика, если контейнер её не хранит
+const BackgroundVideoFPS = 30
+
+// Буфер декодера фонового видео: кадров наперёд и предел памяти под них
 const (
-	MenuState = iota
-	GameState
-	SettingsState
+	VideoBufferFrames = 16
+	VideoMemoryBudget = 48 << 20
 )
 
+// Коды языков, совпадают с именами файлов в assets/locales
 const (
-	LanguageRussian = iota
-	LanguageEnglish
+	LanguageRussian = "ru"
+	LanguageEnglish = "en"
+
+	DefaultLanguage = LanguageRussian
 )
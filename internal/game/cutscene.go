@@ -0,0 +1,244 @@
+package game
+
+import (
+	"aethelgard/internal/audiofile"
+	"aethelgard/internal/input"
+	"aethelgard/internal/mixer"
+	"aethelgard/internal/subtitles"
+	"aethelgard/internal/ui"
+	"aethelgard/internal/video"
+	"image/color"
+	"io/fs"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// SkipHoldTicks — сколько тиков держать кнопку, чтобы пропустить ролик
+const SkipHoldTicks = 60
+
+// Cutscene — ролик со своей звуковой дорожкой и субтитрами
+type Cutscene struct {
+	// Video — каталог кадров или Motion JPEG AVI
+	Video string
+	// Audio — звуковая дорожка (MP3, Ogg Vorbis, WAV); может отсутствовать
+	Audio string
+	// Subtitles — путь без расширения; файл ищется как <путь>.<язык>.vtt или .srt
+	Subtitles string
+}
+
+// IntroCutscene — вступительный ролик новой игры. Пока в ресурсах
+// заглушка: рассвет с названием, субтитры и беззвучная дорожка, по которой
+// идут кадры.
+var IntroCutscene = Cutscene{
+	Video:     "cutscenes/intro.avi",
+	Audio:     "cutscenes/intro.ogg",
+	Subtitles: "cutscenes/intro",
+}
+
+// CutsceneScene проигрывает ролик, привязав кадры к часам звуковой дорожки,
+// и по окончании или пропуску снимается со стека и вызывает onFinish
+type CutsceneScene struct {
+	g        *Game
+	cutscene Cutscene
+	onFinish func()
+
+	video  *VideoPlayer
+	sound  *audio.Player
+	stream *audiofile.Stream
+	subs   *subtitles.Track
+	ui     *ui.Root
+
+	ticks     int64
+	audioPos  time.Duration // последняя позиция дорожки
+	audioTick int64         // тик, на котором она получена
+	armed     bool          // кнопки пропуска были отпущены после входа в сцену
+	finished  bool
+}
+
+// Available сообщает, есть ли видео ролика в ресурсах
+func (c Cutscene) Available(fsys fs.FS) bool {
+	_, err := fs.Stat(fsys, c.Video)
+	return err == nil
+}
+
+func NewCutsceneScene(g *Game, c Cutscene, onFinish func()) *CutsceneScene {
+	s := &CutsceneScene{g: g, cutscene: c, onFinish: onFinish}
+
+	s.ui = ui.NewRoot(g.uiTheme)
+	s.ui.Add(&ui.Caption{
+		Pos: ui.At(ui.Bottom, 0, -60),
+		Lines: func() []string {
+			return s.subs.At(s.position())
+		},
+	})
+	s.ui.Add(&ui.HoldRing{
+		Pos:      ui.At(ui.BottomRight, -60, -50),
+		Radius:   18,
+		Key:      "Hold to skip",
+		Progress: s.skipProgress,
+	})
+	s.ui.Layout()
+	return s
+}
+
+func (s *CutsceneScene) Enter() {
+	player, err := NewVideoPlayer(s.g.assets, s.cutscene.Video, BackgroundVideoFPS, video.Options{
+		BufferFrames: VideoBufferFrames,
+		MemoryBudget: VideoMemoryBudget,
+		Once:         true,
+	})
+	if err != nil {
+		log.Printf("Cutscene: failed to open video %s: %v", s.cutscene.Video, err)
+	} else {
+		s.video = player
+		s.video.SetClock(s.position)
+	}
+
+	if s.cutscene.Audio != "" {
+		if err := s.openSound(); err != nil {
+			log.Printf("Cutscene: failed to open audio %s: %v", s.cutscene.Audio, err)
+		}
+	}
+
+	s.subs = s.g.loadSubtitles(s.cutscene.Subtitles)
+
+	if s.sound != nil {
+		s.sound.Play()
+	}
+}
+
+func (s *CutsceneScene) openSound() error {
+	stream, err := s.g.decodeAudio(s.cutscene.Audio)
+	if err != nil {
+		return err
+	}
+	player, err := s.g.audioContext.NewPlayer(stream)
+	if err != nil {
+		stream.Close()
+		return err
+	}
+	s.g.mixer.Attach(mixer.Voice, player, 1)
+	s.sound = player
+	s.stream = stream
+	return nil
+}
+
+func (s *CutsceneScene) Exit() {
+	if s.video != nil {
+		s.video.Close()
+		s.video = nil
+	}
+	if s.sound != nil {
+		s.g.mixer.Detach(s.sound)
+		s.sound.Close()
+		s.sound = nil
+		s.stream.Close()
+		s.stream = nil
+	}
+}
+
+func (s *CutsceneScene) OnResume() {}
+
+// HandlesBack — Back пропускает ролик только удержанием, а не нажатием
+func (s *CutsceneScene) HandlesBack() bool {
+	return true
+}
+
+func (s *CutsceneScene) Relayout() {
+	s.ui.Layout()
+}
+
+// position — время ролика. Пока дорожка играет, время берётся из неё, и видео
+// следует за звуком; до начала и после конца дорожки время идёт по тикам.
+func (s *CutsceneScene) position() time.Duration {
+	if s.sound != nil && s.sound.IsPlaying() {
+		s.audioPos = s.sound.Current()
+		s.audioTick = s.ticks
+		return s.audioPos
+	}
+	return s.audioPos + time.Duration(s.ticks-s.audioTick)*time.Second/time.Duration(ebiten.TPS())
+}
+
+// skipHeld возвращает, сколько тиков удерживается кнопка пропуска
+func (s *CutsceneScene) skipHeld() int {
+	if !s.armed {
+		return 0
+	}
+	held := 0
+	for _, a := range []input.Action{input.Confirm, input.Back, input.PointerPress} {
+		if d := s.g.input.Duration(a); d > held {
+			held = d
+		}
+	}
+	return held
+}
+
+func (s *CutsceneScene) skipProgress() float64 {
+	return float64(s.skipHeld()) / SkipHoldTicks
+}
+
+func (s *CutsceneScene) Update() error {
+	s.ticks++
+	s.g.updateGlow()
+
+	// Кнопку, которой выбрали пункт меню, нужно сначала отпустить
+	if !s.armed && !s.g.input.Pressed(input.Confirm) && !s.g.input.Pressed(input.Back) && !s.g.input.Pressed(input.PointerPress) {
+		s.armed = true
+	}
+
+	if s.skipHeld() >= SkipHoldTicks {
+		s.finish()
+		return nil
+	}
+
+	if s.video == nil {
+		s.finish()
+		return nil
+	}
+	s.video.Update()
+
+	soundDone := s.sound == nil || !s.sound.IsPlaying()
+	if s.video.Finished() && soundDone {
+		s.finish()
+	}
+	return nil
+}
+
+// finish снимает сцену со стека ровно один раз
+func (s *CutsceneScene) finish() {
+	if s.finished {
+		return
+	}
+	s.finished = true
+	s.g.PopScene()
+	if s.onFinish != nil {
+		s.onFinish()
+	}
+}
+
+func (s *CutsceneScene) Draw(screen *ebiten.Image) {
+	sw, sh := float64(s.g.screenW), float64(s.g.screenH)
+	ebitenutil.DrawRect(screen, 0, 0, sw, sh, color.RGBA{0, 0, 0, 255})
+
+	if s.video != nil {
+		if frame := s.video.CurrentFrame(); frame != nil {
+			// Вписываем кадр в экран с сохранением пропорций
+			w, h := frame.Bounds().Dx(), frame.Bounds().Dy()
+			scale := sw / float64(w)
+			if sy := sh / float64(h); sy < scale {
+				scale = sy
+			}
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Scale(scale, scale)
+			op.GeoM.Translate((sw-float64(w)*scale)/2, (sh-float64(h)*scale)/2)
+			op.Filter = ebiten.FilterLinear
+			screen.DrawImage(frame, op)
+		}
+	}
+
+	s.ui.Draw(screen)
+}
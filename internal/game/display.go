@@ -0,0 +1,219 @@
+package game
+
+import (
+	"aethelgard/internal/settings"
+	"image"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// WindowSize — размер окна в оконном режиме
+type WindowSize struct {
+	Width, Height int
+}
+
+// WindowSizes — размеры окна, которые предлагает экран настроек
+var WindowSizes = []WindowSize{
+	{960, 540},
+	{1280, 720},
+	{1600, 900},
+	{1920, 1080},
+	{2560, 1440},
+}
+
+// UIScales — варианты масштаба интерфейса
+var UIScales = []float64{0.8, 0.9, 1, 1.1, 1.25, 1.5}
+
+// FPSCaps — варианты ограничения частоты кадров; 0 — без ограничения.
+// Ограничение решает, как часто кадр рисуется заново: между ними окно
+// показывает прошлый кадр, а цикл ebiten крутится без ограничения, чтобы
+// логика и ввод не зависели от частоты кадров. С вертикальной
+// синхронизацией кадры идут с частотой монитора, и ограничение не действует.
+var FPSCaps = []int{0, 30, 60, 120, 144, 240}
+
+// TPS — частота обновления логики игры; от настроек экрана она не зависит
+const TPS = 60
+
+// MinWindowWidth, MinWindowHeight — меньше окно сделать нельзя
+const (
+	MinWindowWidth  = 640
+	MinWindowHeight = 360
+)
+
+// applyDisplay применяет настройки экрана; вызывается и до запуска игры,
+// и на лету из экрана настроек
+func (g *Game) applyDisplay(d settings.Display) {
+	prev := g.display
+	g.display = d
+
+	switch d.Mode {
+	case settings.WindowFullscreen:
+		ebiten.SetFullscreen(true)
+	case settings.WindowBorderless:
+		// Окно без рамки размером с монитор
+		ebiten.SetFullscreen(false)
+		ebiten.SetWindowDecorated(false)
+		if w, h := ebiten.ScreenSizeInFullscreen(); w > 0 && h > 0 {
+			ebiten.SetWindowSize(w, h)
+		}
+		ebiten.SetWindowPosition(0, 0)
+	default:
+		ebiten.SetFullscreen(false)
+		ebiten.SetWindowDecorated(true)
+		if prev.Mode != d.Mode || prev.Width != d.Width || prev.Height != d.Height {
+			ebiten.SetWindowSize(d.Width, d.Height)
+			// По центру монитора, в том числе после окна без рамки в углу
+			if w, h := ebiten.ScreenSizeInFullscreen(); w > 0 && h > 0 {
+				ebiten.SetWindowPosition((w-d.Width)/2, (h-d.Height)/2)
+			}
+		}
+	}
+
+	// Логика идёт с постоянной частотой, а кадры — с синхронизацией или
+	// сколько успеем; ограничение кадров пропускает отрисовку в Draw, и
+	// экран очищает сам Draw, когда рисует
+	ebiten.SetTPS(TPS)
+	ebiten.SetScreenClearedEveryFrame(false)
+	if d.VSync {
+		ebiten.SetFPSMode(ebiten.FPSModeVsyncOn)
+		g.pacer.fps = 0
+	} else {
+		ebiten.SetFPSMode(ebiten.FPSModeVsyncOffMaximum)
+		g.pacer.fps = d.FPSCap
+	}
+}
+
+// Допустимые пропорции логического экрана; в окне за их пределами
+// появляются полосы
+const (
+	MinAspect = 4.0 / 3.0
+	MaxAspect = 32.0 / 9.0
+)
+
+// Layout отдаёт экран в физических пикселях окна: кадр рисуется на холсте
+// логического размера и масштабируется под окно в Draw
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	scale := ebiten.DeviceScaleFactor()
+	g.outsideW = int(math.Ceil(float64(outsideWidth) * scale))
+	g.outsideH = int(math.Ceil(float64(outsideHeight) * scale))
+	return g.outsideW, g.outsideH
+}
+
+// logicalSize — размер холста для экрана окна w×h. При растягивании холст
+// всегда ScreenWidth×ScreenHeight; при целом масштабе он покрывает окно целиком
+// пикселями кратного размера; иначе высота холста ScreenHeight, а ширина
+// следует пропорциям окна.
+func logicalSize(scaling string, w, h int) (int, int) {
+	if w <= 0 || h <= 0 || scaling == settings.ScalingStretch {
+		return ScreenWidth, ScreenHeight
+	}
+	if scaling == settings.ScalingInteger {
+		if k := int(math.Min(float64(w)/ScreenWidth, float64(h)/ScreenHeight)); k >= 1 {
+			return w / k, h / k
+		}
+	}
+	aspect := math.Max(MinAspect, math.Min(MaxAspect, float64(w)/float64(h)))
+	return int(math.Round(ScreenHeight * aspect)), ScreenHeight
+}
+
+// updateScreen подстраивает холст, масштаб интерфейса и шрифты под размер окна
+// и настройки; раскладка сцен пересчитывается, только если что-то изменилось
+func (g *Game) updateScreen() {
+	w, h := logicalSize(g.display.Scaling, g.outsideW, g.outsideH)
+
+	// Интерфейс нарисован под ScreenWidth×ScreenHeight: на холсте другого
+	// размера он масштабируется, чтобы поместиться, и ещё на выбор игрока
+	scale := math.Min(float64(w)/ScreenWidth, float64(h)/ScreenHeight) * g.display.UIScale
+	if w == g.screenW && h == g.screenH && scale == g.uiTheme.Scale {
+		return
+	}
+
+	if g.canvas != nil && (w != g.screenW || h != g.screenH) {
+		g.canvas.Dispose()
+		g.canvas = nil
+	}
+	g.screenW, g.screenH = w, h
+	g.uiTheme.Screen = image.Rect(0, 0, w, h)
+
+	if scale != g.uiTheme.Scale {
+		g.uiTheme.Scale = scale
+		g.titleFont = g.face(FontFamilyTitle, math.Round(TitleFontSize*scale))
+		g.menuFont = g.face(FontFamilyMenu, math.Round(MenuFontSize*scale))
+		g.uiTheme.TitleFont = g.titleFont
+		g.uiTheme.BodyFont = g.menuFont
+	}
+
+	g.relayoutScenes()
+}
+
+// viewport — положение холста на экране
+type viewport struct {
+	scaleX, scaleY float64
+	offX, offY     float64
+}
+
+// fitViewport вписывает холст w×h в экран по способу масштабирования
+func fitViewport(scaling string, w, h, screenW, screenH int) viewport {
+	sx := float64(screenW) / float64(w)
+	sy := float64(screenH) / float64(h)
+
+	switch scaling {
+	case settings.ScalingStretch:
+		return viewport{scaleX: sx, scaleY: sy}
+	case settings.ScalingInteger:
+		// В окне меньше холста целого масштаба нет — уменьшаем с сохранением пропорций
+		if s := math.Floor(math.Min(sx, sy)); s >= 1 {
+			sx, sy = s, s
+		}
+	}
+
+	s := math.Min(sx, sy)
+	return viewport{
+		scaleX: s,
+		scaleY: s,
+		offX:   math.Floor((float64(screenW) - float64(w)*s) / 2),
+		offY:   math.Floor((float64(screenH) - float64(h)*s) / 2),
+	}
+}
+
+// GeoM переводит координаты холста в координаты экрана
+func (v viewport) GeoM() ebiten.GeoM {
+	var m ebiten.GeoM
+	m.Scale(v.scaleX, v.scaleY)
+	m.Translate(v.offX, v.offY)
+	return m
+}
+
+// toCanvas переводит координаты экрана (курсора) в координаты холста
+func (v viewport) toCanvas(x, y int) (int, int) {
+	if v.scaleX == 0 || v.scaleY == 0 {
+		return x, y
+	}
+	return int(math.Floor((float64(x) - v.offX) / v.scaleX)), int(math.Floor((float64(y) - v.offY) / v.scaleY))
+}
+
+// framePacer ограничивает частоту отрисовки кадров
+type framePacer struct {
+	fps  int
+	next time.Time
+}
+
+// due сообщает, пора ли рисовать новый кадр. Кадры идут по сетке с шагом
+// 1/fps, чтобы частота не проседала из-за опоздания на долю шага; после
+// долгой паузы сетка начинается заново.
+func (p *framePacer) due(now time.Time) bool {
+	if p.fps <= 0 {
+		return true
+	}
+	if now.Before(p.next) {
+		return false
+	}
+	step := time.Second / time.Duration(p.fps)
+	p.next = p.next.Add(step)
+	if now.Sub(p.next) >= step {
+		p.next = now.Add(step)
+	}
+	return true
+}
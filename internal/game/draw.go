@@ -1,19 +1,52 @@
 package game
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"aethelgard/internal/settings"
+	"time"
 
-// Draw отрисовывает текущее состояние игры
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Draw отрисовывает текущее состояние игры на холсте и выводит холст на экран
+// по выбранному способу масштабирования
 func (g *Game) Draw(screen *ebiten.Image) {
-	// Рисуем фон
-	g.DrawBackground(screen)
-
-	// Отрисовываем текущее состояние
-	switch g.state {
-	case MenuState:
-		g.DrawMenu(screen)
-	case SettingsState:
-		g.DrawSettings(screen)
-	case GameState:
-		g.DrawGame(screen)
+	// При ограничении кадров экран не очищается, и пропущенный кадр
+	// показывает прошлый. После смены размера окна экран новый, его
+	// надо нарисовать сразу.
+	w, h := screen.Size()
+	if !g.pacer.due(time.Now()) && w == g.drawnW && h == g.drawnH {
+		return
+	}
+	g.drawnW, g.drawnH = w, h
+
+	if g.canvas == nil {
+		g.canvas = ebiten.NewImage(g.screenW, g.screenH)
+	}
+	g.canvas.Clear()
+
+	// Рисуем фон, если верхняя сцена не закрывает его целиком
+	scene := g.CurrentScene()
+	if o, ok := scene.(opaqueScene); !ok || !o.Opaque() {
+		g.DrawBackground(g.canvas)
+	}
+
+	// Отрисовываем верхнюю сцену
+	if scene != nil {
+		scene.Draw(g.canvas)
+	}
+	g.drawNotice(g.canvas)
+
+	if g.showDebug {
+		g.drawDebugOverlay(g.canvas)
+	}
+
+	g.view = fitViewport(g.display.Scaling, g.screenW, g.screenH, w, h)
+	op := &ebiten.DrawImageOptions{GeoM: g.view.GeoM()}
+	if g.display.Scaling == settings.ScalingInteger || g.view.scaleX == 1 && g.view.scaleY == 1 {
+		op.Filter = ebiten.FilterNearest
+	} else {
+		op.Filter = ebiten.FilterLinear
 	}
+	screen.Clear()
+	screen.DrawImage(g.canvas, op)
 }
@@ -2,6 +2,7 @@ package game
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -14,15 +15,17 @@ func (g *Game) DrawBackground(screen *ebiten.Image) {
 		videoFrame = g.videoPlayer.CurrentFrame()
 	}
 
+	sw, sh := float64(g.screenW), float64(g.screenH)
 	if videoFrame != nil {
+		// Кадр покрывает экран любых пропорций, лишнее обрезается по краям
+		w, h := float64(videoFrame.Bounds().Dx()), float64(videoFrame.Bounds().Dy())
+		scale := math.Max(sw/w, sh/h)
 		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Scale(
-			float64(ScreenWidth)/float64(videoFrame.Bounds().Dx()),
-			float64(ScreenHeight)/float64(videoFrame.Bounds().Dy()),
-		)
+		op.GeoM.Scale(scale, scale)
+		op.GeoM.Translate((sw-w*scale)/2, (sh-h*scale)/2)
 		op.ColorM.Scale(0.4, 0.4, 0.4, 1.0)
 		screen.DrawImage(videoFrame, op)
 	} else {
-		ebitenutil.DrawRect(screen, 0, 0, ScreenWidth, ScreenHeight, color.RGBA{0, 0, 0, 255})
+		ebitenutil.DrawRect(screen, 0, 0, sw, sh, color.RGBA{0, 0, 0, 255})
 	}
 }
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"aethelgard/internal/input"
+	"aethelgard/internal/ui"
+	"image/color"
+)
+
+// controlLabels — ключи локализации действий, которые можно переназначить,
+// в порядке на странице. Основную кнопку указателя не переназначаем: без
+// неё не нажать ни одну кнопку мышью.
+var controlLabels = []struct {
+	action input.Action
+	key    string
+}{
+	{input.MenuUp, "Up"},
+	{input.MenuDown, "Down"},
+	{input.MenuLeft, "Left"},
+	{input.MenuRight, "Right"},
+	{input.Confirm, "Confirm"},
+	{input.Back, "Back"},
+	{input.ValueDecrease, "Decrease"},
+	{input.ValueIncrease, "Increase"},
+	{input.Attack, "Attack"},
+	{input.Pause, "Pause"},
+	{input.QuickSave, "Quick Save"},
+	{input.QuickLoad, "Quick Load"},
+	{input.SaveMenu, "Save Menu"},
+	{input.ZoomIn, "Zoom In"},
+	{input.ZoomOut, "Zoom Out"},
+	{input.DebugOverlay, "Debug Info"},
+}
+
+// controlRows — строк в столбце страницы управления
+const controlRows = 8
+
+// buildControlsUI строит страницу управления. Кнопка действия показывает
+// его привязку для устройства, которым игрок пользовался последним, и по
+// нажатию ждёт новую кнопку клавиатуры или геймпада, смотря чем игрок
+// пользуется; мышь при этом управляет страницей. Переназначение сразу
+// записывается в файл настроек.
+func (g *Game) buildControlsUI() *ui.Root {
+	root := ui.NewRoot(g.uiTheme)
+
+	root.Add(&ui.Label{
+		Key:             "Controls",
+		Font:            ui.FontTitle,
+		Pos:             panelAt(0, 100),
+		Align:           ui.AlignCenter,
+		Color:           color.RGBA{230, 220, 200, 255},
+		ShadowOffset:    2,
+		ShadowAlpha:     100,
+		Underline:       true,
+		UnderlineOffset: 20,
+	})
+
+	var capturing input.Action
+	for i, c := range controlLabels {
+		c := c
+		x := -540 + i/controlRows*560
+		y := 165 + i%controlRows*52
+
+		root.Add(&ui.Label{
+			Key:          c.key,
+			Pos:          panelAt(x, y+30),
+			Color:        color.RGBA{200, 190, 180, 255},
+			ShadowOffset: 2,
+			ShadowAlpha:  100,
+		})
+		root.Add(&ui.Button{
+			Box: panelBox(x+320, y, x+520, y+42),
+			Dynamic: func() string {
+				if capturing == c.action && g.input.Capturing() {
+					return g.getText("Press a key")
+				}
+				if glyph, ok := g.input.Glyph(c.action); ok {
+					return glyph.Label
+				}
+				return "—"
+			},
+			OnClick: func() {
+				g.playSound(CueMenuConfirm)
+				capturing = c.action
+				g.rebind(c.action)
+			},
+		})
+	}
+
+	root.Add(&ui.Button{
+		Key: "Reset",
+		Box: panelBox(-210, 615, -10, 660),
+		OnClick: func() {
+			g.playSound(CueMenuConfirm)
+			g.input.SetBindings(input.DefaultBindings())
+			g.saveSettings()
+		},
+	})
+	root.Add(&ui.Button{
+		Key:     "Back",
+		Box:     panelBox(10, 615, 210, 660),
+		OnClick: g.goBack,
+	})
+
+	root.Add(&ui.PromptBar{
+		Pos: ui.At(ui.BottomLeft, 60, -12),
+		Prompts: func() []ui.Prompt {
+			return []ui.Prompt{
+				g.prompt("Change", input.Confirm),
+				g.prompt("Back", input.Back),
+			}
+		},
+	})
+
+	root.Layout()
+	return root
+}
+
+// reservedControls — действия, без которых не пройти по меню. Их кнопки
+// нельзя отдать другим действиям: такая кнопка, нажатая во время
+// переназначения, отменяет его, поэтому «Назад» и работает как отмена.
+var reservedControls = []input.Action{
+	input.Back,
+	input.Confirm,
+	input.PointerPress,
+	input.MenuUp,
+	input.MenuDown,
+	input.MenuLeft,
+	input.MenuRight,
+}
+
+// rebind ждёт новую кнопку для действия
+func (g *Game) rebind(a input.Action) {
+	g.input.Rebind(a, reservedControls, func(b input.Binding, ok bool) {
+		if !ok {
+			g.playSound(CueMenuBack)
+			return
+		}
+		g.playSound(CueMenuConfirm)
+		g.saveSettings()
+	})
+}
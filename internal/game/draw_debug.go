@@ -0,0 +1,38 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// drawDebugOverlay выводит FPS, состояние декодера фонового видео и отрисовки карты
+func (g *Game) drawDebugOverlay(screen *ebiten.Image) {
+	msg := fmt.Sprintf("FPS: %.1f  TPS: %.1f", ebiten.ActualFPS(), ebiten.ActualTPS())
+
+	if g.videoPlayer != nil {
+		s := g.videoPlayer.Stats()
+		msg += fmt.Sprintf("\nVideo buffer: %d/%d frames\nDecoded: %d  Dropped: %d  Errors: %d\nMemory: %.1f / %.1f MB",
+			s.Buffered, s.Capacity, s.Decoded, s.Dropped, s.Errors,
+			float64(s.MemoryBytes)/(1<<20), float64(s.MemoryBudget)/(1<<20))
+	}
+
+	if g.world != nil && g.session != nil {
+		s := g.world.renderer.Stats()
+		msg += fmt.Sprintf("\nChunks: %d loaded, %d drawn\nTiles: %d  Draw calls: %d",
+			s.LoadedChunks, s.DrawnChunks, s.Tiles, s.DrawCalls)
+		c := g.world.camera
+		msg += fmt.Sprintf("\nEntities: %d  Systems: %d", g.world.ecs.Len(), len(g.world.systems.Systems()))
+		if atlas := g.world.sprites; atlas != nil {
+			b := atlas.Image.Bounds()
+			msg += fmt.Sprintf("\nAtlas: %dx%d, %d sprites", b.Dx(), b.Dy(), len(atlas.Sheets))
+		}
+		if a := g.world.c.Animator.Get(g.world.c.FindHero()); a != nil {
+			msg += fmt.Sprintf("\nHero: %s %s #%d", a.State, a.Dir, a.Frame)
+		}
+		msg += fmt.Sprintf("\nCamera: %.0f, %.0f  Zoom: %.2f  Trauma: %.2f", c.X, c.Y, c.Zoom, c.Shake.Trauma)
+	}
+
+	ebitenutil.DebugPrintAt(screen, msg, 10, 10)
+}
@@ -0,0 +1,231 @@
+package game
+
+import (
+	"aethelgard/internal/input"
+	"aethelgard/internal/settings"
+	"aethelgard/internal/ui"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+)
+
+// displayOption — вариант карусели: ключ локализации и значение настройки
+type displayOption struct {
+	key   string
+	value string
+}
+
+var windowModes = []displayOption{
+	{"Windowed", settings.WindowWindowed},
+	{"Borderless", settings.WindowBorderless},
+	{"Fullscreen", settings.WindowFullscreen},
+}
+
+var scalingModes = []displayOption{
+	{"Pixel Perfect", settings.ScalingInteger},
+	{"Letterbox", settings.ScalingLetterbox},
+	{"Stretch", settings.ScalingStretch},
+}
+
+// buildDisplayUI строит страницу настроек экрана. Изменения применяются
+// сразу, а записываются в файл при выходе со страницы.
+func (g *Game) buildDisplayUI() *ui.Root {
+	root := ui.NewRoot(g.uiTheme)
+
+	root.Add(&ui.Label{
+		Key:             "Display",
+		Font:            ui.FontTitle,
+		Pos:             panelAt(0, 100),
+		Align:           ui.AlignCenter,
+		Color:           color.RGBA{230, 220, 200, 255},
+		ShadowOffset:    2,
+		ShadowAlpha:     100,
+		Underline:       true,
+		UnderlineOffset: 20,
+	})
+
+	rows := []struct {
+		key      string
+		items    func() []string
+		index    func() int
+		onChange func(i int)
+	}{
+		{
+			key:   "Window Mode",
+			items: func() []string { return g.optionLabels(windowModes) },
+			index: func() int { return optionIndex(windowModes, g.display.Mode) },
+			onChange: func(i int) {
+				d := g.display
+				d.Mode = windowModes[i].value
+				g.applyDisplay(d)
+			},
+		},
+		{
+			key: "Window Size",
+			items: func() []string {
+				labels := make([]string, len(WindowSizes))
+				for i, size := range WindowSizes {
+					labels[i] = fmt.Sprintf("%d x %d", size.Width, size.Height)
+				}
+				return labels
+			},
+			index: func() int {
+				for i, size := range WindowSizes {
+					if size.Width == g.display.Width && size.Height == g.display.Height {
+						return i
+					}
+				}
+				return 0
+			},
+			onChange: func(i int) {
+				d := g.display
+				d.Width, d.Height = WindowSizes[i].Width, WindowSizes[i].Height
+				g.applyDisplay(d)
+			},
+		},
+		{
+			key:   "VSync",
+			items: func() []string { return []string{g.getText("Off"), g.getText("On")} },
+			index: func() int {
+				if g.display.VSync {
+					return 1
+				}
+				return 0
+			},
+			onChange: func(i int) {
+				d := g.display
+				d.VSync = i == 1
+				g.applyDisplay(d)
+			},
+		},
+		{
+			key: "FPS Limit",
+			items: func() []string {
+				labels := make([]string, len(FPSCaps))
+				for i, fps := range FPSCaps {
+					if fps == 0 {
+						labels[i] = g.getText("Unlimited")
+					} else {
+						labels[i] = strconv.Itoa(fps)
+					}
+				}
+				return labels
+			},
+			index: func() int {
+				for i, fps := range FPSCaps {
+					if fps == g.display.FPSCap {
+						return i
+					}
+				}
+				return 0
+			},
+			onChange: func(i int) {
+				d := g.display
+				d.FPSCap = FPSCaps[i]
+				g.applyDisplay(d)
+			},
+		},
+		{
+			key:   "Scaling",
+			items: func() []string { return g.optionLabels(scalingModes) },
+			index: func() int { return optionIndex(scalingModes, g.display.Scaling) },
+			onChange: func(i int) {
+				d := g.display
+				d.Scaling = scalingModes[i].value
+				g.applyDisplay(d)
+			},
+		},
+		{
+			key: "UI Scale",
+			items: func() []string {
+				labels := make([]string, len(UIScales))
+				for i, scale := range UIScales {
+					labels[i] = fmt.Sprintf("%d%%", int(math.Round(scale*100)))
+				}
+				return labels
+			},
+			index: func() int {
+				for i, scale := range UIScales {
+					if scale == g.display.UIScale {
+						return i
+					}
+				}
+				// Масштаб, заданный в файле вручную, показываем как 100%
+				for i, scale := range UIScales {
+					if scale == 1 {
+						return i
+					}
+				}
+				return 0
+			},
+			onChange: func(i int) {
+				// Раскладка пересчитается в начале следующего тика
+				d := g.display
+				d.UIScale = UIScales[i]
+				g.applyDisplay(d)
+			},
+		},
+	}
+
+	for i, row := range rows {
+		row := row
+		y := 165 + i*72
+
+		root.Add(&ui.Label{
+			Key:          row.key,
+			Pos:          panelAt(-330, y+34),
+			Color:        color.RGBA{200, 190, 180, 255},
+			ShadowOffset: 2,
+			ShadowAlpha:  100,
+		})
+		root.Add(&ui.Carousel{
+			Box:   panelBox(-60, y, 300, y+50),
+			Items: row.items,
+			Index: row.index,
+			OnChange: func(i int) {
+				g.playSound(CueMenuConfirm)
+				row.onChange(i)
+			},
+		})
+	}
+
+	root.Add(&ui.Button{
+		Key:     "Back",
+		Box:     panelBox(-100, 615, 100, 660),
+		OnClick: g.goBack,
+	})
+
+	root.Add(&ui.PromptBar{
+		Pos: ui.At(ui.BottomLeft, 60, -12),
+		Prompts: func() []ui.Prompt {
+			return []ui.Prompt{
+				g.prompt("Select", input.Confirm),
+				g.prompt("Adjust", input.ValueDecrease, input.ValueIncrease),
+				g.prompt("Back", input.Back),
+			}
+		},
+	})
+
+	root.Layout()
+	return root
+}
+
+// optionLabels переводит подписи вариантов карусели
+func (g *Game) optionLabels(options []displayOption) []string {
+	labels := make([]string, len(options))
+	for i, o := range options {
+		labels[i] = g.getText(o.key)
+	}
+	return labels
+}
+
+// optionIndex ищет вариант по значению настройки
+func optionIndex(options []displayOption, value string) int {
+	for i, o := range options {
+		if o.value == value {
+			return i
+		}
+	}
+	return 0
+}
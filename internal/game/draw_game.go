@@ -1,6 +1,9 @@
 package game
 
 import (
+	"aethelgard/internal/i18n"
+	"aethelgard/internal/input"
+	"aethelgard/internal/ui"
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -8,24 +11,58 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/text"
 )
 
+// buildGameUI строит подсказки управления поверх игры; значки берутся из
+// текущих привязок, поэтому после переназначения подсказка не врёт
+func (g *Game) buildGameUI() *ui.Root {
+	root := ui.NewRoot(g.uiTheme)
+	root.Add(&ui.PromptBar{
+		Pos: ui.At(ui.BottomLeft, 40, -24),
+		Prompts: func() []ui.Prompt {
+			return []ui.Prompt{
+				g.prompt("Main Menu", input.Back),
+				g.prompt("Settings", input.Pause),
+			}
+		},
+	})
+	root.Layout()
+	return root
+}
+
 // DrawGame отрисовывает игровое состояние
-func (g *Game) DrawGame(screen *ebiten.Image) {
-	ebitenutil.DrawRect(screen, 0, 0, ScreenWidth, ScreenHeight, color.RGBA{0, 0, 0, 200})
+func (g *Game) DrawGame(screen *ebiten.Image, root *ui.Root) {
+	g.drawWorld(screen)
+
+	t := g.uiTheme
 
-	gameText := g.getText("Game Started")
-	bounds := text.BoundString(g.titleFont, gameText)
-	textWidth := bounds.Max.X - bounds.Min.X
-	textX := ScreenWidth/2 - int(textWidth)/2
-	textY := ScreenHeight / 2
+	// Локация, уровень и время игры
+	if g.session != nil {
+		info := g.getText(g.session.Location) + " · " + g.formatText("Level {n}", i18n.Vars{"n": g.session.Player.Level}) + " · " + formatPlayTime(g.session.PlayTime)
+		pos := ui.At(ui.TopLeft, 40, 50).Resolve(t)
+		text.Draw(screen, info, g.menuFont, pos.X+t.Px(2), pos.Y+t.Px(2), color.RGBA{0, 0, 0, 200})
+		text.Draw(screen, info, g.menuFont, pos.X, pos.Y, color.RGBA{230, 220, 200, 255})
+	}
+
+	g.drawMessage(screen)
+
+	root.Draw(screen)
+}
 
-	text.Draw(screen, gameText, g.titleFont, textX+3, textY+3, color.RGBA{0, 0, 0, 200})
-	text.Draw(screen, gameText, g.titleFont, textX, textY, color.RGBA{220, 200, 180, 255})
+// drawMessage выводит открытую надпись в рамке внизу экрана
+func (g *Game) drawMessage(screen *ebiten.Image) {
+	if g.world == nil || g.world.message == "" {
+		return
+	}
 
-	hintText := g.getText("Press ESC")
-	hintBounds := text.BoundString(g.menuFont, hintText)
-	hintWidth := hintBounds.Max.X - hintBounds.Min.X
-	hintX := ScreenWidth/2 - int(hintWidth)/2
-	hintY := textY + 80
+	t := g.uiTheme
+	r := ui.Box{At: ui.At(ui.Bottom, 0, -110), W: 760, H: 90, Pivot: ui.Bottom}.Resolve(t)
+	border := float64(t.Px(2))
+	ebitenutil.DrawRect(screen, float64(r.Min.X)-border, float64(r.Min.Y)-border,
+		float64(r.Dx())+2*border, float64(r.Dy())+2*border, color.RGBA{230, 220, 200, 200})
+	ebitenutil.DrawRect(screen, float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy()), color.RGBA{20, 18, 24, 235})
 
-	text.Draw(screen, hintText, g.menuFont, hintX, hintY, color.RGBA{180, 170, 160, 200})
+	s := g.getText(g.world.message)
+	b := text.BoundString(g.menuFont, s)
+	x := r.Min.X + (r.Dx()-b.Dx())/2
+	y := r.Min.Y + (r.Dy()+b.Dy())/2
+	text.Draw(screen, s, g.menuFont, x, y, color.RGBA{230, 220, 200, 255})
 }
@@ -1,6 +1,8 @@
 package game
 
 import (
+	"aethelgard/internal/input"
+	"aethelgard/internal/ui"
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -8,64 +10,58 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/text"
 )
 
+// buildMenuUI строит пункты главного меню
+func (g *Game) buildMenuUI() *ui.Root {
+	root := ui.NewRoot(g.uiTheme)
+
+	// Пункты меню — у левого края, чуть выше середины экрана
+	for i, item := range g.menuItems {
+		index := i
+		root.Add(&ui.MenuEntry{
+			Key:        item.label,
+			Pos:        ui.At(ui.Left, 80, -40+i*60),
+			OnActivate: func() { g.handleMenuAction(index) },
+		})
+	}
+
+	root.Add(&ui.PromptBar{
+		Pos: ui.At(ui.BottomLeft, 60, -12),
+		Prompts: func() []ui.Prompt {
+			return []ui.Prompt{
+				g.prompt("Select", input.Confirm),
+				g.prompt("Exit", input.Back),
+			}
+		},
+	})
+
+	root.Layout()
+	root.SetFocus(0)
+	return root
+}
+
 // DrawMenu отрисовывает главное меню
-func (g *Game) DrawMenu(screen *ebiten.Image) {
+func (g *Game) DrawMenu(screen *ebiten.Image, root *ui.Root) {
+	t := g.uiTheme
 	title := "Aethelgard"
-	titleX := 60
-	titleY := 120
+	pos := ui.At(ui.TopLeft, 60, 120).Resolve(t)
+	titleX, titleY := pos.X, pos.Y
 
 	for i := 5; i > 0; i-- {
 		shadowAlpha := uint8(30 * i)
-		text.Draw(screen, title, g.titleFont, titleX+i, titleY+i, color.RGBA{0, 0, 0, shadowAlpha})
+		text.Draw(screen, title, g.titleFont, titleX+t.Px(i), titleY+t.Px(i), color.RGBA{0, 0, 0, shadowAlpha})
 	}
 
 	text.Draw(screen, title, g.titleFont, titleX, titleY, color.RGBA{230, 220, 200, 255})
 
 	subtitle := "Realms Unbound"
-	subtitleY := titleY + 40
-	text.Draw(screen, subtitle, g.menuFont, titleX+10, subtitleY, color.RGBA{180, 170, 150, 200})
+	subtitleY := titleY + t.Px(40)
+	text.Draw(screen, subtitle, g.menuFont, titleX+t.Px(10), subtitleY, color.RGBA{180, 170, 150, 200})
 
 	titleBounds := text.BoundString(g.titleFont, title)
 	titleWidth := titleBounds.Max.X - titleBounds.Min.X
-	ebitenutil.DrawRect(screen, float64(titleX), float64(subtitleY+10), float64(titleWidth), 2, color.RGBA{180, 170, 150, 100})
-
-	menuX := 80
-	startY := 320
-
-	for i, item := range g.menuItems {
-		itemY := startY + i*60
-		isSelected := i == g.selectedIndex
-
-		itemText := g.getText(item.label)
-		textBounds := text.BoundString(g.menuFont, itemText)
-		textWidth := textBounds.Max.X - textBounds.Min.X
-
-		var textColor color.RGBA
-		if isSelected {
-			glowValue := uint8(220 + 35*g.glowIntensity)
-
-			lineY := float64(itemY + 8)
-			lineWidth := float64(textWidth + 10)
-
-			for j := 0; j < 3; j++ {
-				glowAlpha := uint8(float64(60-j*15) * g.glowIntensity)
-				ebitenutil.DrawRect(screen, float64(menuX-5-j), lineY+float64(j), lineWidth+float64(j*2), 1, color.RGBA{180, 140, 255, glowAlpha})
-			}
-
-			ebitenutil.DrawRect(screen, float64(menuX-5), lineY, lineWidth, 2, color.RGBA{200, 160, 255, uint8(200 * g.glowIntensity)})
-
-			dotX := float64(menuX - 25)
-			dotY := float64(itemY - 8)
-			g.drawGlowingDot(screen, dotX, dotY, g.glowIntensity)
+	ebitenutil.DrawRect(screen, float64(titleX), float64(subtitleY+t.Px(10)), float64(titleWidth), float64(t.Px(2)), color.RGBA{180, 170, 150, 100})
 
-			textColor = color.RGBA{glowValue, glowValue - 20, 255, 255}
-		} else {
-			textColor = color.RGBA{150, 140, 130, 200}
-		}
-
-		text.Draw(screen, itemText, g.menuFont, menuX+2, itemY+2, color.RGBA{0, 0, 0, 100})
-		text.Draw(screen, itemText, g.menuFont, menuX, itemY, textColor)
-	}
+	root.Draw(screen)
 
 	g.drawBottomDecoration(screen)
 }
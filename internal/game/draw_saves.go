@@ -0,0 +1,190 @@
+package game
+
+import (
+	"aethelgard/internal/i18n"
+	"aethelgard/internal/input"
+	"aethelgard/internal/save"
+	"aethelgard/internal/ui"
+	"errors"
+	"image/color"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// slotsMode — для чего открыт список слотов
+type slotsMode int
+
+const (
+	slotsLoad slotsMode = iota
+	slotsSave
+)
+
+// Сетка карточек: два столбца по пять строк
+const (
+	slotRows       = 5
+	slotCardWidth  = 560
+	slotCardHeight = 100
+	slotCardGap    = 10
+)
+
+// SaveDateLayout — формат даты сохранения на карточке
+const SaveDateLayout = "2006-01-02 15:04"
+
+// slotTitle — название слота для карточки
+func (g *Game) slotTitle(slot save.Slot) string {
+	switch slot.Kind {
+	case save.Quick:
+		return g.getText("Quicksave")
+	case save.Auto:
+		return g.formatText("Autosave {n}", i18n.Vars{"n": slot.Index})
+	default:
+		return g.formatText("Slot {n}", i18n.Vars{"n": slot.Index})
+	}
+}
+
+// buildSaveSlotsUI строит список слотов. При загрузке показываются все
+// сохранения, новые первыми, и их число; при сохранении — ручные слоты по
+// порядку, включая пустые.
+func (g *Game) buildSaveSlotsUI(s *SaveSlotsScene) *ui.Root {
+	root := ui.NewRoot(g.uiTheme)
+
+	title := "Load Game"
+	if s.mode == slotsSave {
+		title = "Save Game"
+	}
+	root.Add(&ui.Label{
+		Key:             title,
+		Font:            ui.FontTitle,
+		Pos:             panelAt(0, 100),
+		Align:           ui.AlignCenter,
+		Color:           color.RGBA{230, 220, 200, 255},
+		ShadowOffset:    2,
+		ShadowAlpha:     100,
+		Underline:       true,
+		UnderlineOffset: 20,
+	})
+
+	var metas []save.Meta
+	if g.saves != nil {
+		list, err := g.saves.List()
+		if err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		metas = list
+	}
+
+	var slots []save.Slot
+	bySlot := make(map[save.Slot]save.Meta, len(metas))
+	for _, m := range metas {
+		bySlot[m.Slot] = m
+		if s.mode == slotsLoad {
+			slots = append(slots, m.Slot)
+		}
+	}
+	if s.mode == slotsSave {
+		for i := 1; i <= save.ManualSlots; i++ {
+			slots = append(slots, save.ManualSlot(i))
+		}
+	}
+
+	if s.mode == slotsLoad && len(slots) > 0 {
+		count := len(slots)
+		root.Add(&ui.Label{
+			Dynamic:      func() string { return g.pluralText("Saved Games", count, nil) },
+			Pos:          ui.At(ui.BottomRight, -60, -12),
+			Align:        ui.AlignRight,
+			Color:        color.RGBA{150, 140, 130, 200},
+			ShadowOffset: 2,
+			ShadowAlpha:  100,
+		})
+	}
+
+	if len(slots) == 0 {
+		root.Add(&ui.Label{
+			Key:          "No Saves",
+			Pos:          panelAt(0, 360),
+			Align:        ui.AlignCenter,
+			Color:        color.RGBA{200, 190, 180, 255},
+			ShadowOffset: 2,
+			ShadowAlpha:  100,
+		})
+	}
+
+	for i, slot := range slots {
+		slot := slot
+		x := -slotCardWidth - slotCardGap
+		if i >= slotRows {
+			x = slotCardGap
+		}
+		y := 150 + i%slotRows*(slotCardHeight+slotCardGap)
+
+		card := &ui.SlotCard{
+			Box:   panelBox(x, y, x+slotCardWidth, y+slotCardHeight),
+			Title: g.slotTitle(slot),
+		}
+		if m, ok := bySlot[slot]; ok && errors.Is(m.Err, save.ErrTooNew) {
+			card.Details = []string{g.getText("Save Too New")}
+		} else if ok && m.Err != nil {
+			card.Details = []string{g.getText("Save Damaged")}
+		} else if ok {
+			card.Stamp = m.SavedAt.Local().Format(SaveDateLayout)
+			card.Details = []string{
+				g.getText(m.Location) + " · " + g.formatText("Level {n}", i18n.Vars{"n": m.Level}) + " · " + formatPlayTime(m.PlayTime),
+			}
+			card.Thumbnail = decodeThumbnail(m.Thumbnail)
+			if card.Thumbnail != nil {
+				s.thumbnails = append(s.thumbnails, card.Thumbnail)
+			}
+		} else {
+			card.Details = []string{g.getText("Empty Slot")}
+		}
+
+		if s.mode == slotsSave {
+			card.OnClick = func() { s.saveTo(slot) }
+		} else {
+			card.OnClick = func() { g.loadGame(slot) }
+		}
+		root.Add(card)
+	}
+
+	root.Add(&ui.PromptBar{
+		Pos: ui.At(ui.BottomLeft, 60, -12),
+		Prompts: func() []ui.Prompt {
+			return []ui.Prompt{
+				g.prompt("Select", input.Confirm),
+				g.prompt("Back", input.Back),
+			}
+		},
+	})
+
+	root.Layout()
+	root.SetFocus(0)
+	return root
+}
+
+// DrawSaveSlots отрисовывает список слотов
+func (g *Game) DrawSaveSlots(screen *ebiten.Image, root *ui.Root) {
+	ebitenutil.DrawRect(screen, 0, 0, float64(g.screenW), float64(g.screenH), color.RGBA{0, 0, 0, 200})
+
+	root.Draw(screen)
+}
+
+// drawNotice выводит сообщение о сохранении в правом верхнем углу
+func (g *Game) drawNotice(screen *ebiten.Image) {
+	if g.notice == "" || time.Now().After(g.noticeUntil) {
+		return
+	}
+
+	t := g.uiTheme
+	s := g.getText(g.notice)
+	b := text.BoundString(g.menuFont, s)
+	pos := ui.At(ui.TopRight, -40, 50).Resolve(t)
+	x := pos.X - b.Dx()
+
+	text.Draw(screen, s, g.menuFont, x+t.Px(2), pos.Y+t.Px(2), color.RGBA{0, 0, 0, 200})
+	text.Draw(screen, s, g.menuFont, x, pos.Y, color.RGBA{230, 220, 200, 255})
+}
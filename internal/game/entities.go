@@ -0,0 +1,324 @@
+package game
+
+import (
+	"aethelgard/internal/camera"
+	"aethelgard/internal/ecs"
+	"aethelgard/internal/entities"
+	"aethelgard/internal/physics"
+	"aethelgard/internal/player"
+	"aethelgard/internal/save"
+	"aethelgard/internal/sprite"
+	"aethelgard/internal/tilemap"
+	"image/color"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Объекты карты, из которых рождаются сущности. У объекта со свойством
+// text можно прочитать надпись или услышать реплику (ключ локализации);
+// таблички и объекты со свойством solid=true непроходимы; жители (npc)
+// бродят в пределах radius пикселей от места появления и выглядят как
+// спрайт из свойства sprite (без него — NPCSprite).
+const (
+	ObjectSign = "sign"
+	ObjectNPC  = "npc"
+	PropText   = "text"
+	PropSolid  = "solid"
+	PropRadius = "radius"
+	PropSprite = "sprite"
+)
+
+// HeroName — имя сущности героя
+const HeroName = "hero"
+
+// DefaultWanderRadius — радиус прогулок жителя без свойства radius
+const DefaultWanderRadius = 64.0
+
+// TalkPause — сколько секунд житель стоит после реплики
+const TalkPause = 3.0
+
+// Внешний вид сущностей, если их спрайты не загрузились; по фигурам
+// же сущности отмечаются на миникарте
+var (
+	heroFigure = entities.Figure{Color: [3]uint8{60, 70, 130}, W: 20, H: 30}
+	npcFigure  = entities.Figure{Color: [3]uint8{120, 90, 60}, W: 20, H: 28}
+)
+
+// populateWorld заселяет мир с карты: герой в точке появления (без неё —
+// в центре карты), таблички, жители и прочие объекты со свойствами
+func (g *Game) populateWorld() {
+	w := g.world
+	c := w.c
+
+	for _, o := range w.m.Objects {
+		switch {
+		case o.Type == ObjectNPC:
+			g.spawnNPC(o)
+		case o.Width > 0 && o.Height > 0:
+			g.spawnProp(o)
+		}
+	}
+
+	var x, y float64
+	if spawn, ok := w.m.Object(SpawnObject); ok {
+		x, y = spawn.X, spawn.Y
+	} else {
+		b := w.m.PixelBounds()
+		x, y = float64(b.Min.X+b.Dx()/2), float64(b.Min.Y+b.Dy()/2)
+	}
+	hero := w.ecs.Spawn()
+	c.Hero.Add(hero, entities.Hero{})
+	c.Name.Add(hero, entities.Name{Name: HeroName})
+	c.Position.Add(hero, entities.Position{X: x, Y: y})
+	c.Walker.Add(hero, *player.New(x, y))
+	c.Figure.Add(hero, heroFigure)
+	g.animate(hero, HeroSprite)
+}
+
+// spawnProp создаёт неподвижный объект: табличку, дверь, сундук. Точка
+// у ног — середина нижнего края объекта.
+func (g *Game) spawnProp(o tilemap.Object) {
+	c := g.world.c
+	solid := o.Properties[PropSolid] == "true" || o.Type == ObjectSign && o.Properties[PropSolid] != "false"
+	text := o.Properties[PropText]
+	if !solid && text == "" {
+		return
+	}
+
+	e := g.world.ecs.Spawn()
+	c.Position.Add(e, entities.Position{X: o.X + o.Width/2, Y: o.Y + o.Height})
+	c.Collider.Add(e, entities.Collider{
+		Hitbox: physics.R(-o.Width/2, -o.Height, o.Width/2, 0),
+		Solid:  solid,
+	})
+	if text != "" {
+		c.Interactable.Add(e, entities.Interactable{Text: text})
+	}
+	if o.Name != "" {
+		c.Name.Add(e, entities.Name{Name: o.Name})
+	}
+}
+
+// spawnNPC создаёт жителя, который бродит вокруг места появления
+func (g *Game) spawnNPC(o tilemap.Object) {
+	c := g.world.c
+	radius := DefaultWanderRadius
+	if v, err := strconv.ParseFloat(o.Properties[PropRadius], 64); err == nil {
+		radius = v
+	}
+
+	e := g.world.ecs.Spawn()
+	c.Position.Add(e, entities.Position{X: o.X, Y: o.Y})
+	walker := player.New(o.X, o.Y)
+	walker.MaxSpeed = player.DefaultMaxSpeed / 2
+	c.Walker.Add(e, *walker)
+	c.Wander.Add(e, entities.Wander{HomeX: o.X, HomeY: o.Y, Radius: radius, Seed: uint64(o.ID)})
+	c.Figure.Add(e, npcFigure)
+	look := NPCSprite
+	if v := o.Properties[PropSprite]; v != "" {
+		look = v
+	}
+	g.animate(e, look)
+	if text := o.Properties[PropText]; text != "" {
+		c.Interactable.Add(e, entities.Interactable{Text: text})
+	}
+	if o.Name != "" {
+		c.Name.Add(e, entities.Name{Name: o.Name})
+	}
+}
+
+// restoreEntities берёт сущности из сессии, а если их там нет или они
+// не читаются — заселяет мир с карты. Герой старого сохранения без
+// сущностей встаёт туда, где был сохранён.
+func (g *Game) restoreEntities() {
+	w := g.world
+	w.ecs.Clear()
+
+	restored := false
+	if g.session != nil && g.session.Entities != nil {
+		skipped, err := w.ecs.Restore(g.session.Entities)
+		switch {
+		case err != nil:
+			log.Printf("Warning: failed to restore entities, repopulating from map: %v", err)
+		case w.c.FindHero() == ecs.None:
+			log.Printf("Warning: saved entities have no hero, repopulating from map")
+			w.ecs.Clear()
+		default:
+			restored = true
+		}
+		if len(skipped) > 0 {
+			log.Printf("Warning: unknown components in save ignored: %v", skipped)
+		}
+	}
+
+	if !restored {
+		g.populateWorld()
+		if g.session != nil && g.session.Player.Position != nil {
+			p := g.session.Player.Position
+			hero := w.c.FindHero()
+			*w.c.Position.Get(hero) = entities.Position{X: p.X, Y: p.Y}
+			w.c.Walker.Get(hero).Facing = player.ParseDirection(p.Facing)
+		}
+	}
+
+	// Сохранения до появления спрайтов не знают аниматоров
+	if restored {
+		if hero := w.c.FindHero(); !w.c.Animator.Has(hero) {
+			g.animate(hero, HeroSprite)
+		}
+		w.c.Wander.Each(func(e ecs.Entity, _ *entities.Wander) {
+			if !w.c.Animator.Has(e) {
+				g.animate(e, NPCSprite)
+			}
+		})
+	}
+
+	// Контроллеры начинают с места, чтобы отрисовка не интерполировала
+	// от прежнего положения; скорость из сохранения остаётся
+	ecs.Each2(w.c.Walker, w.c.Position, func(_ ecs.Entity, ctl *player.Controller, pos *entities.Position) {
+		vx, vy := ctl.VX, ctl.VY
+		ctl.Place(pos.X, pos.Y)
+		ctl.VX, ctl.VY = vx, vy
+	})
+}
+
+// heroPosition возвращает положение героя для отрисовки, между двумя
+// последними шагами симуляции
+func (g *Game) heroPosition() (float64, float64) {
+	w := g.world
+	hero := w.c.FindHero()
+	if ctl := w.c.Walker.Get(hero); ctl != nil {
+		return ctl.Interpolated(w.clock.Alpha())
+	}
+	if pos := w.c.Position.Get(hero); pos != nil {
+		return pos.X, pos.Y
+	}
+	return w.camera.X, w.camera.Y
+}
+
+// interact открывает надпись или реплику сущности перед героем. Житель
+// поворачивается к герою и ненадолго останавливается.
+func (g *Game) interact() {
+	w := g.world
+	c := w.c
+	hero := c.FindHero()
+	ctl, pos := c.Walker.Get(hero), c.Position.Get(hero)
+	if ctl == nil || pos == nil {
+		return
+	}
+
+	c.SyncBodies(w.physics)
+	ctl.X, ctl.Y = pos.X, pos.Y
+	body, ok := ctl.Interactable(w.physics, func(b physics.Body) bool {
+		return c.Interactable.Has(ecs.Entity(b.ID))
+	})
+	if !ok {
+		return
+	}
+	e := ecs.Entity(body.ID)
+	w.message = c.Interactable.Get(e).Text
+	g.playSound(CueMenuConfirm)
+
+	if wd := c.Wander.Get(e); wd != nil {
+		wd.Stop(TalkPause)
+	}
+	if npc, npcPos := c.Walker.Get(e), c.Position.Get(e); npc != nil && npcPos != nil {
+		if d, ok := player.DirectionOf(pos.X-npcPos.X, pos.Y-npcPos.Y); ok {
+			npc.Facing = d
+		}
+	}
+}
+
+// syncSession переносит состояние мира в сессию перед сохранением
+func (g *Game) syncSession() {
+	if g.session == nil || g.world == nil {
+		return
+	}
+	w := g.world
+
+	snap, err := w.ecs.Snapshot()
+	if err != nil {
+		log.Printf("Warning: failed to snapshot entities: %v", err)
+	} else {
+		g.session.Entities = snap
+	}
+
+	hero := w.c.FindHero()
+	if pos, ctl := w.c.Position.Get(hero), w.c.Walker.Get(hero); pos != nil && ctl != nil {
+		g.session.Player.Position = &save.Position{X: pos.X, Y: pos.Y, Facing: ctl.Facing.String()}
+	}
+}
+
+// renderFrame — цель отрисовки для систем фазы отрисовки: часть экрана
+// камеры, сама камера и доля шага для сглаживания движения
+type renderFrame struct {
+	screen *ebiten.Image
+	cam    *camera.Camera
+	alpha  float64
+}
+
+// drawActors — система отрисовки: спрайты и фигуры сущностей сверху вниз
+// по точке у ног, чтобы стоящий ниже перекрывал стоящего выше. Сущность
+// с аниматором, чей спрайт не загрузился, рисуется фигурой.
+func (g *Game) drawActors(w *ecs.World, _ float64) {
+	f := ecs.Resource[renderFrame](w)
+	c := g.world.c
+	if f == nil || f.screen == nil {
+		return
+	}
+
+	type item struct {
+		x, y   float64
+		frame  *sprite.Frame
+		flipX  bool
+		fig    *entities.Figure
+		walker *player.Controller
+	}
+	var items []item
+	c.Position.Each(func(e ecs.Entity, pos *entities.Position) {
+		it := item{x: pos.X, y: pos.Y, fig: c.Figure.Get(e), walker: c.Walker.Get(e)}
+		if a := c.Animator.Get(e); a != nil {
+			it.frame, it.flipX = g.spriteFrame(a)
+		}
+		if it.frame == nil && it.fig == nil {
+			return
+		}
+		if it.walker != nil {
+			it.x, it.y = it.walker.Interpolated(f.alpha)
+		}
+		items = append(items, it)
+	})
+	sort.SliceStable(items, func(i, j int) bool { return items[i].y < items[j].y })
+
+	camGeoM := f.cam.GeoM()
+	z := f.cam.Zoom
+	for _, it := range items {
+		if it.frame != nil {
+			// Кадр встаёт на целый пиксель мира, иначе он дрожит при ходьбе
+			var geoM ebiten.GeoM
+			geoM.Translate(math.Round(it.x), math.Round(it.y))
+			geoM.Concat(camGeoM)
+			it.frame.Draw(f.screen, geoM, it.flipX)
+			continue
+		}
+
+		sx, sy := f.cam.WorldToScreen(it.x, it.y)
+		w, h := it.fig.W*z, it.fig.H*z
+		clr := color.RGBA{it.fig.Color[0], it.fig.Color[1], it.fig.Color[2], 255}
+		ebitenutil.DrawRect(f.screen, sx-w/2, sy-h, w, h, clr)
+		ebitenutil.DrawRect(f.screen, sx-w/2+2*z, sy-h+2*z, w-4*z, 10*z, color.RGBA{230, 200, 170, 255})
+
+		// Метка взгляда
+		if it.walker != nil {
+			dx, dy := it.walker.Facing.Vector()
+			m := 4 * z
+			fx, fy := sx+dx*14*z, sy-h/2+dy*14*z
+			ebitenutil.DrawRect(f.screen, fx-m/2, fy-m/2, m, m, color.RGBA{255, 230, 120, 255})
+		}
+	}
+}
@@ -1,25 +1,93 @@
 package game
 
 import (
+	"aethelgard/internal/fonts"
 	"log"
-	"os"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
 )
 
-var TanaFont []byte
-var HudSonicFont []byte
+// Семейства шрифтов игры
+const (
+	FontFamilyTitle    = "Tana Uncial SP"
+	FontFamilyMenu     = "HUD Sonic X1"
+	FontFamilyFallback = "Bebas Neue Cyrillic"
+)
 
-func init() {
-	// Заголовочный шрифт (Tana Uncial SP)
-	tana, err := os.ReadFile("assets/fonts/TanaUncialSP.ttf")
-	if err != nil {
-		log.Fatal("Failed to load Tana Uncial SP font: ", err)
+// Кегли интерфейса
+const (
+	TitleFontSize = 72
+	MenuFontSize  = 28
+)
+
+// fontFiles — файлы шрифтов по семействам, пути внутри файловой системы ресурсов
+var fontFiles = []struct {
+	family string
+	path   string
+}{
+	{FontFamilyTitle, "fonts/TanaUncialSP.ttf"},
+	{FontFamilyMenu, "fonts/HUD-Sonic-X1.otf"},
+	{FontFamilyFallback, "AethelgardFont.ttf"},
+}
+
+// loadFonts регистрирует шрифты и цепочки запасных семейств. Отсутствующий
+// файл не останавливает игру: символы берутся из следующих шрифтов цепочки.
+func (g *Game) loadFonts() {
+	g.fonts = fonts.NewManager()
+	for _, f := range fontFiles {
+		if err := g.fonts.RegisterFile(g.assets, f.family, f.path); err != nil {
+			log.Printf("Warning: %v", err)
+		}
 	}
-	TanaFont = tana
 
-	// Шрифт меню (HUD Sonic X1)
-	hud, err := os.ReadFile("assets/fonts/HUD-Sonic-X1.otf")
+	g.fonts.SetFallbacks(FontFamilyTitle, FontFamilyMenu, FontFamilyFallback)
+	g.fonts.SetFallbacks(FontFamilyMenu, FontFamilyFallback)
+
+	g.titleFont = g.face(FontFamilyTitle, TitleFontSize)
+	g.menuFont = g.face(FontFamilyMenu, MenuFontSize)
+}
+
+// face возвращает начертание семейства; если ни одного шрифта цепочки
+// загрузить не удалось, используется встроенный растровый шрифт
+func (g *Game) face(family string, size float64) font.Face {
+	f, err := g.fonts.Face(family, size, fonts.DefaultDPI)
 	if err != nil {
-		log.Fatal("Failed to load HUD Sonic X1 font: ", err)
+		log.Printf("Warning: %v, using built-in font", err)
+		return basicfont.Face7x13
+	}
+	return f
+}
+
+// checkGlyphs предупреждает о строках текущего языка, которые нечем отрисовать
+func (g *Game) checkGlyphs() {
+	if g.fonts == nil || g.translator == nil {
+		return
+	}
+
+	for _, lang := range g.languages {
+		if lang.Code != g.language {
+			continue
+		}
+		for _, family := range lang.Fonts {
+			if !g.fonts.Has(family) {
+				log.Printf("Warning: language %s needs font %q which is not loaded", lang.Code, family)
+			}
+		}
+	}
+
+	var all strings.Builder
+	for _, item := range g.menuItems {
+		all.WriteString(g.getText(item.label))
+	}
+	for _, key := range []string{"Settings", "Language", "Volume", "Master", "Music", "Sound Effects", "Voice", "Ambience", "Mute", "Display", "Back", "Select", "Adjust"} {
+		all.WriteString(g.getText(key))
+	}
+
+	for _, family := range []string{FontFamilyTitle, FontFamilyMenu} {
+		if missing := g.fonts.Missing(family, all.String()); len(missing) > 0 {
+			log.Printf("Warning: font %q and its fallbacks have no glyphs for %q", family, string(missing))
+		}
 	}
-	HudSonicFont = hud
 }
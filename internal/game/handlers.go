@@ -3,19 +3,28 @@ package game
 import "os"
 
 func (g *Game) handleMenuAction(index int) {
+	g.playSound(CueMenuConfirm)
 	switch g.menuItems[index].label {
 	case "New Game":
-		g.state = GameState
+		g.startNewGame()
+	case "Load Game":
+		g.PushScene(NewLoadGameScene(g))
 	case "Settings":
-		g.state = SettingsState
+		g.PushScene(NewSettingsScene(g))
 	case "Exit":
 		os.Exit(0)
 	}
-
-	// Обновляем состояние музыки после изменения состояния игры
-	g.updateMusicState()
 }
 
-func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return ScreenWidth, ScreenHeight
-}
\ No newline at end of file
+// startNewGame показывает вступительный ролик, если он есть, и начинает игру
+func (g *Game) startNewGame() {
+	g.session = newSession()
+	g.lastAutosave = 0
+	if !IntroCutscene.Available(g.assets) {
+		g.PushScene(NewGameScene(g))
+		return
+	}
+	g.PushScene(NewCutsceneScene(g, IntroCutscene, func() {
+		g.PushScene(NewGameScene(g))
+	}))
+}
@@ -1,82 +1,59 @@
 package game
 
 import (
+	"aethelgard/internal/input"
+	"aethelgard/internal/mixer"
+	"aethelgard/internal/ui"
+	"aethelgard/internal/video"
+	"errors"
+	"io/fs"
 	"log"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/opentype"
 )
 
-func NewGame() *Game {
-	// Видео
-	videoPlayer, err := NewVideoPlayer("assets/menu-background-video.mp4", 30)
-	if err != nil {
-		log.Printf("Failed to init VideoPlayer: %v, falling back to static image", err)
-
-		img, _, err := ebitenutil.NewImageFromFile("assets/background.png")
+// NewGame создаёт игру; все ресурсы читаются из assets
+func NewGame(assets fs.FS) *Game {
+	// Видео: первый ролик из списка, который удалось открыть, иначе картинка
+	var videoPlayer *VideoPlayer
+	for _, path := range BackgroundVideos {
+		player, err := NewVideoPlayer(assets, path, BackgroundVideoFPS, video.Options{
+			BufferFrames: VideoBufferFrames,
+			MemoryBudget: VideoMemoryBudget,
+		})
 		if err != nil {
-			log.Fatal("Failed to load fallback background: ", err)
-		}
-
-		videoPlayer = &VideoPlayer{
-			framePaths:   nil,
-			currentIndex: 0,
-			frameCount:   1,
-			frameDelay:   1,
-			frameTimer:   0,
-			fps:          0,
-			currentFrame: img,
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("VideoPlayer: %s: %v", path, err)
+			}
+			continue
 		}
+		videoPlayer = player
+		break
 	}
 
-	// === Заголовочный шрифт (Tana Uncial SP) ===
-	ttTitle, err := opentype.Parse(TanaFont)
-	if err != nil {
-		log.Fatal("Failed to parse Tana Uncial SP font:", err)
-	}
-
-	titleFace, err := opentype.NewFace(ttTitle, &opentype.FaceOptions{
-		Size:    72,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		log.Fatal("Failed to create title font:", err)
-	}
-
-	// === Шрифт меню (HUD Sonic X1) ===
-	ttMenu, err := opentype.Parse(HudSonicFont)
-	if err != nil {
-		log.Fatal("Failed to parse HUD Sonic X1 font:", err)
-	}
+	if videoPlayer == nil {
+		log.Printf("Failed to init VideoPlayer, falling back to static image")
 
-	menuFace, err := opentype.NewFace(ttMenu, &opentype.FaceOptions{
-		Size:    28,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		log.Fatal("Failed to create menu font:", err)
+		img, _, err := ebitenutil.NewImageFromFileSystem(assets, "background.png")
+		if err != nil {
+			log.Fatal("Failed to load fallback background: ", err)
+		}
+		videoPlayer = NewStaticVideoPlayer(img)
 	}
 
 	// Аудио
-	audioContext := audio.NewContext(44100)
+	audioContext := audio.NewContext(SampleRate)
 
 	// === Создаём игру ===
 	game := &Game{
-		state:         MenuState,
-		language:      LanguageRussian,
+		assets:        assets,
 		videoPlayer:   videoPlayer,
-		titleFont:     titleFace, // Tana Uncial SP
-		menuFont:      menuFace,  // HUD Sonic X1
-		selectedIndex: 0,
 		glowIntensity: 0,
 		glowDirection: 0.02,
-		keyPressed:    false,
+		input:         input.NewManager(input.DefaultBindings()),
 		audioContext:  audioContext,
-		masterVolume:  0.7,
+		mixer:         mixer.New(),
 		menuItems: []MenuItem{
 			{"New Game", false},
 			{"Load Game", false},
@@ -85,10 +62,24 @@ func NewGame() *Game {
 		},
 	}
 
-	// Музыка
-	if err := game.loadAndPlayBackgroundMusic(); err != nil {
-		log.Printf("Warning: Failed to load background music: %v", err)
+	// Шрифты с запасными цепочками, таблицы строк, затем громкость, язык и привязки управления из файла настроек
+	game.loadFonts()
+	game.loadLocales()
+	game.loadSettings()
+
+	game.uiTheme = &ui.Theme{
+		TitleFont: game.titleFont,
+		BodyFont:  game.menuFont,
+		Translate: game.getText,
+		Glow:      game.glowIntensity,
 	}
+	game.updateScreen()
+
+	// Музыка запускается, когда на стек попадает первая сцена
+	game.loadMusic()
+	game.loadSounds()
+	game.loadSaves()
+	game.PushScene(NewMenuScene(game))
 
 	return game
 }
@@ -1,49 +1,103 @@
 package game
 
-func (g *Game) getText(key string) string {
-	switch g.language {
-	case LanguageRussian:
-		switch key {
-		case "New Game":
-			return "Новая игра"
-		case "Load Game":
-			return "Загрузить игру"
-		case "Settings":
-			return "Настройки"
-		case "Exit":
-			return "Выход"
-		case "Language":
-			return "Язык"
-		case "Back":
-			return "Назад"
-		case "Game Started":
-			return "Игра началась!"
-		case "Press ESC":
-			return "Нажмите ESC, чтобы вернуться в меню"
-		case "Volume":
-			return "Громкость"
+import (
+	"aethelgard/internal/i18n"
+	"aethelgard/internal/subtitles"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// loadLocales загружает таблицы строк из каталога locales ресурсов и сообщает о ключах,
+// которых нет в каком-либо языке
+func (g *Game) loadLocales() {
+	g.locales = i18n.NewBundle(LanguageEnglish)
+	if err := g.locales.LoadDir(g.assets, "locales"); err != nil {
+		log.Printf("Warning: failed to load locales: %v", err)
+	}
+
+	g.languages = g.locales.Languages()
+	names := make([]string, len(g.languages))
+	for i, lang := range g.languages {
+		names[i] = fmt.Sprintf("%s (%s)", lang.Code, lang.Name)
+	}
+	log.Printf("Found %d languages: %s", len(g.languages), strings.Join(names, ", "))
+
+	for locale, keys := range g.locales.MissingKeys() {
+		log.Printf("Warning: locale %s is missing %d keys: %s", locale, len(keys), strings.Join(keys, ", "))
+	}
+}
+
+// setLanguage переключает язык и перераскладывает виджеты всех сцен,
+// так как ширина переведённых строк меняется; неизвестный язык
+// заменяется языком по умолчанию
+func (g *Game) setLanguage(code string) {
+	if g.locales.Catalog(code) == nil {
+		log.Printf("Warning: language %q is not available, using %q", code, DefaultLanguage)
+		code = DefaultLanguage
+	}
+	g.language = code
+	g.translator = g.locales.Translator(code)
+	g.checkGlyphs()
+	g.relayoutScenes()
+}
+
+// languageIndex возвращает позицию текущего языка в списке найденных
+func (g *Game) languageIndex() int {
+	for i, lang := range g.languages {
+		if lang.Code == g.language {
+			return i
 		}
-	case LanguageEnglish:
-		switch key {
-		case "New Game":
-			return "New Game"
-		case "Load Game":
-			return "Load Game"
-		case "Settings":
-			return "Settings"
-		case "Exit":
-			return "Exit"
-		case "Language":
-			return "Language"
-		case "Back":
-			return "Back"
-		case "Game Started":
-			return "Game Started!"
-		case "Press ESC":
-			return "Press ESC to return to menu"
-		case "Volume":
-			return "Volume"
+	}
+	return 0
+}
+
+// loadSubtitles загружает субтитры <base>.<язык>.vtt или .srt для текущего
+// языка, а если их нет — для языка по умолчанию или английского
+func (g *Game) loadSubtitles(base string) *subtitles.Track {
+	if base == "" {
+		return nil
+	}
+
+	for _, lang := range []string{g.language, DefaultLanguage, LanguageEnglish} {
+		for _, ext := range []string{".vtt", ".srt"} {
+			name := base + "." + lang + ext
+			f, err := g.assets.Open(name)
+			if err != nil {
+				continue
+			}
+			track, err := subtitles.Parse(f)
+			f.Close()
+			if err != nil {
+				log.Printf("Warning: failed to parse subtitles %s: %v", name, err)
+				continue
+			}
+			return track
 		}
 	}
-	return key
-}
\ No newline at end of file
+	return nil
+}
+
+func (g *Game) getText(key string) string {
+	if g.translator == nil {
+		return key
+	}
+	return g.translator.T(key)
+}
+
+// formatText переводит ключ и подставляет {имя}-параметры
+func (g *Game) formatText(key string, vars i18n.Vars) string {
+	if g.translator == nil {
+		return key
+	}
+	return g.translator.Format(key, vars)
+}
+
+// pluralText переводит ключ в форме множественного числа для n и
+// подставляет параметры; {count} — само n
+func (g *Game) pluralText(key string, n int, vars i18n.Vars) string {
+	if g.translator == nil {
+		return key
+	}
+	return g.translator.Plural(key, n, vars)
+}
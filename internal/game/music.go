@@ -0,0 +1,33 @@
+package game
+
+import (
+	"aethelgard/internal/music"
+	"time"
+)
+
+// MusicCrossfade — длительность перехода между музыкой сцен
+const MusicCrossfade = 1500 * time.Millisecond
+
+// Плейлисты сцен. Боя в игре пока нет, и своей музыки у него тоже.
+const (
+	PlaylistMenu        = "menu"
+	PlaylistExploration = "exploration"
+)
+
+// musicPlaylists — музыка сцен. Точки петли задаются в сэмплах при SampleRate;
+// трек, которого нет в ресурсах, пропускается с предупреждением.
+var musicPlaylists = []music.Playlist{
+	{
+		Name: PlaylistMenu,
+		// Вступление — первые шесть секунд, дальше петля до конца файла
+		Tracks: []music.Track{{Path: "music/menu_theme.wav", Loop: true, LoopStart: 6 * SampleRate}},
+	},
+	{
+		Name:    PlaylistExploration,
+		Shuffle: true,
+		Tracks: []music.Track{
+			{Path: "music/exploration_1.wav"},
+			{Path: "music/exploration_2.wav"},
+		},
+	},
+}
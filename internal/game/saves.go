@@ -0,0 +1,180 @@
+package game
+
+import (
+	"aethelgard/internal/save"
+	"aethelgard/internal/settings"
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Размер миниатюры кадра в сохранении
+const (
+	SaveThumbnailWidth  = 320
+	SaveThumbnailHeight = 180
+)
+
+// AutosaveInterval — как часто игра сохраняется сама, по времени игры
+const AutosaveInterval = 5 * time.Minute
+
+// StartLocation — ключ локализации локации, с которой начинается новая игра
+const StartLocation = "Old Road"
+
+// NoticeDuration — сколько висит сообщение о сохранении
+const NoticeDuration = 2 * time.Second
+
+// loadSaves открывает каталог сохранений; без него игра работает,
+// но сохраняться не может
+func (g *Game) loadSaves() {
+	dir, err := settings.Dir()
+	if err != nil {
+		log.Printf("Warning: save location unavailable, saving disabled: %v", err)
+		return
+	}
+	g.saves = save.NewStore(save.Dir(dir))
+}
+
+// newSession начинает состояние новой игры
+func newSession() *save.State {
+	return &save.State{
+		Location: StartLocation,
+		Player:   save.Player{Level: 1},
+	}
+}
+
+// captureThumbnail уменьшает последний кадр до миниатюры в PNG. Вызывается
+// из Update, пока на холсте ещё кадр игры, а не экран сохранения.
+func (g *Game) captureThumbnail() []byte {
+	if g.canvas == nil {
+		return nil
+	}
+
+	w, h := g.canvas.Size()
+	thumb := ebiten.NewImage(SaveThumbnailWidth, SaveThumbnailHeight)
+	defer thumb.Dispose()
+
+	// Кадр любых пропорций обрезается по центру до 16:9
+	scale := float64(SaveThumbnailHeight) / float64(h)
+	if sw := float64(SaveThumbnailWidth) / float64(w); sw > scale {
+		scale = sw
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate((SaveThumbnailWidth-float64(w)*scale)/2, (SaveThumbnailHeight-float64(h)*scale)/2)
+	op.Filter = ebiten.FilterLinear
+	thumb.DrawImage(g.canvas, op)
+
+	img := image.NewRGBA(image.Rect(0, 0, SaveThumbnailWidth, SaveThumbnailHeight))
+	thumb.ReadPixels(img.Pix)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		log.Printf("Warning: failed to encode save thumbnail: %v", err)
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// decodeThumbnail превращает миниатюру сохранения в картинку для карточки
+func decodeThumbnail(data []byte) *ebiten.Image {
+	if len(data) == 0 {
+		return nil
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		log.Printf("Warning: bad save thumbnail: %v", err)
+		return nil
+	}
+	return ebiten.NewImageFromImage(img)
+}
+
+// saveGame записывает текущую игру в слот
+func (g *Game) saveGame(slot save.Slot, thumbnail []byte) bool {
+	if g.saves == nil || g.session == nil {
+		return false
+	}
+	g.syncSession()
+	if _, err := g.saves.Save(slot, *g.session, thumbnail); err != nil {
+		log.Printf("Warning: failed to save game: %v", err)
+		g.showNotice("Save Failed")
+		return false
+	}
+	log.Printf("Game saved to slot %v", slot)
+	g.showNotice("Game Saved")
+	return true
+}
+
+// autosave записывает текущую игру в кольцо автосохранений
+func (g *Game) autosave() {
+	if g.saves == nil || g.session == nil {
+		return
+	}
+	g.lastAutosave = g.session.PlayTime
+	g.syncSession()
+	meta, err := g.saves.Autosave(*g.session, g.captureThumbnail())
+	if err != nil {
+		log.Printf("Warning: autosave failed: %v", err)
+		return
+	}
+	log.Printf("Autosaved to slot %v", meta.Slot)
+}
+
+// loadGame загружает игру из слота: сцены над главным меню закрываются
+// и открывается игра с загруженным состоянием
+func (g *Game) loadGame(slot save.Slot) {
+	if g.saves == nil {
+		return
+	}
+	f, err := g.saves.Load(slot)
+	if err != nil {
+		log.Printf("Warning: failed to load game: %v", err)
+		g.showNotice("Load Failed")
+		return
+	}
+
+	// Игра, которую сменяет загрузка, не должна вытеснить из кольца
+	// автосохранений ни одно хорошее сохранение
+	g.loading = true
+	g.returnToMenu()
+	g.loading = false
+	g.session = &f.State
+	g.lastAutosave = f.State.PlayTime
+	g.PushScene(NewGameScene(g))
+	log.Printf("Game loaded from slot %v", slot)
+}
+
+// returnToMenu снимает со стека все сцены над главным меню
+func (g *Game) returnToMenu() {
+	for len(g.scenes.scenes) > 1 {
+		g.PopScene()
+	}
+}
+
+// updateSession ведёт время игры, автосохранение и быстрые сохранения
+func (g *Game) updateSession() {
+	if g.session == nil {
+		return
+	}
+	g.session.PlayTime += time.Second / time.Duration(ebiten.TPS())
+
+	if g.session.PlayTime-g.lastAutosave >= AutosaveInterval {
+		g.autosave()
+	}
+}
+
+// showNotice показывает короткое сообщение поверх игры
+func (g *Game) showNotice(key string) {
+	g.notice = key
+	g.noticeUntil = time.Now().Add(NoticeDuration)
+}
+
+// formatPlayTime выводит время игры как 1:05:09
+func formatPlayTime(d time.Duration) string {
+	s := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+}
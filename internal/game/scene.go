@@ -0,0 +1,108 @@
+package game
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Scene — экран игры, живущий на стеке сцен Game
+type Scene interface {
+	// Enter вызывается, когда сцена помещается на стек
+	Enter()
+	// Exit вызывается, когда сцена снимается со стека
+	Exit()
+	// Update обновляет сцену; вызывается только для верхней сцены
+	Update() error
+	// Draw отрисовывает сцену поверх фона
+	Draw(screen *ebiten.Image)
+	// OnResume вызывается, когда сцена снова оказывается наверху стека
+	OnResume()
+}
+
+// musicScene — сцена со своей фоновой музыкой: плейлист и его уровень относительно
+// громкости шины. Пустой плейлист оставляет играть тот, что звучал под сценой.
+// Если верхняя сцена его не реализует, музыка затихает.
+type musicScene interface {
+	Playlist() string
+	MusicLevel() float64
+}
+
+// layoutScene — сцена с виджетами, которые нужно перераскладывать при смене
+// языка или шрифтов, даже если она сейчас не наверху стека
+type layoutScene interface {
+	Relayout()
+}
+
+// backScene — сцена, которая сама обрабатывает Back; без него нажатие Back
+// снимает верхнюю сцену
+type backScene interface {
+	HandlesBack() bool
+}
+
+// opaqueScene — сцена, которая целиком закрывает экран; под ней фон
+// меню не рисуется
+type opaqueScene interface {
+	Opaque() bool
+}
+
+// sceneStack — стек сцен, верхняя сцена получает ввод и управляет музыкой
+type sceneStack struct {
+	scenes []Scene
+}
+
+func (s *sceneStack) push(scene Scene) {
+	s.scenes = append(s.scenes, scene)
+}
+
+func (s *sceneStack) pop() Scene {
+	if len(s.scenes) == 0 {
+		return nil
+	}
+	top := s.scenes[len(s.scenes)-1]
+	s.scenes[len(s.scenes)-1] = nil
+	s.scenes = s.scenes[:len(s.scenes)-1]
+	return top
+}
+
+func (s *sceneStack) top() Scene {
+	if len(s.scenes) == 0 {
+		return nil
+	}
+	return s.scenes[len(s.scenes)-1]
+}
+
+func (s *sceneStack) empty() bool {
+	return len(s.scenes) == 0
+}
+
+// PushScene кладёт сцену на вершину стека
+func (g *Game) PushScene(scene Scene) {
+	g.scenes.push(scene)
+	scene.Enter()
+	g.updateMusicState()
+}
+
+// PopScene снимает верхнюю сцену и возвращает управление той, что её открыла
+func (g *Game) PopScene() {
+	top := g.scenes.pop()
+	if top == nil {
+		return
+	}
+	top.Exit()
+
+	if next := g.scenes.top(); next != nil {
+		next.OnResume()
+	}
+	g.updateMusicState()
+}
+
+// CurrentScene возвращает верхнюю сцену стека
+func (g *Game) CurrentScene() Scene {
+	return g.scenes.top()
+}
+
+// relayoutScenes перераскладывает виджеты всех сцен стека
+func (g *Game) relayoutScenes() {
+	for _, scene := range g.scenes.scenes {
+		if l, ok := scene.(layoutScene); ok {
+			l.Relayout()
+		}
+	}
+}
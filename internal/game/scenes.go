@@ -0,0 +1,244 @@
+package game
+
+import (
+	"aethelgard/internal/save"
+	"aethelgard/internal/ui"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MenuScene — главное меню, корень стека сцен
+type MenuScene struct {
+	g  *Game
+	ui *ui.Root
+}
+
+func NewMenuScene(g *Game) *MenuScene {
+	return &MenuScene{g: g, ui: g.buildMenuUI()}
+}
+
+func (s *MenuScene) Enter() {
+	s.ui.Reset()
+}
+
+func (s *MenuScene) Exit() {}
+
+func (s *MenuScene) OnResume() {
+	s.ui.Reset()
+}
+
+func (s *MenuScene) Relayout() {
+	s.ui.Layout()
+}
+
+func (s *MenuScene) Update() error {
+	s.g.updateMenu(s.ui)
+	return nil
+}
+
+func (s *MenuScene) Draw(screen *ebiten.Image) {
+	s.g.DrawMenu(screen, s.ui)
+}
+
+// Playlist — тема главного меню
+func (s *MenuScene) Playlist() string {
+	return PlaylistMenu
+}
+
+// MusicLevel — в главном меню музыка играет на полную громкость
+func (s *MenuScene) MusicLevel() float64 {
+	return 1.0
+}
+
+// SettingsScene — экран настроек; открывается поверх меню или игры.
+// Страница экрана (Display) — та же сцена с другим деревом виджетов.
+type SettingsScene struct {
+	g  *Game
+	ui *ui.Root
+}
+
+func NewSettingsScene(g *Game) *SettingsScene {
+	return &SettingsScene{g: g, ui: g.buildSettingsUI()}
+}
+
+// NewDisplaySettingsScene открывает страницу настроек экрана
+func NewDisplaySettingsScene(g *Game) *SettingsScene {
+	return &SettingsScene{g: g, ui: g.buildDisplayUI()}
+}
+
+// NewControlsSettingsScene открывает страницу переназначения управления
+func NewControlsSettingsScene(g *Game) *SettingsScene {
+	return &SettingsScene{g: g, ui: g.buildControlsUI()}
+}
+
+func (s *SettingsScene) Enter() {
+	s.ui.Reset()
+}
+
+// Exit прерывает ожидание кнопки и сохраняет настройки при выходе с экрана
+func (s *SettingsScene) Exit() {
+	s.g.input.CancelRebind()
+	s.g.saveSettings()
+}
+
+func (s *SettingsScene) OnResume() {
+	s.ui.Reset()
+}
+
+func (s *SettingsScene) Relayout() {
+	s.ui.Layout()
+}
+
+func (s *SettingsScene) Update() error {
+	s.g.updateSettings(s.ui)
+	return nil
+}
+
+func (s *SettingsScene) Draw(screen *ebiten.Image) {
+	s.g.DrawSettings(screen, s.ui)
+}
+
+// Playlist — настройки не меняют музыку сцены, поверх которой открыты
+func (s *SettingsScene) Playlist() string {
+	return ""
+}
+
+// MusicLevel — в настройках музыка приглушена до 20%
+func (s *SettingsScene) MusicLevel() float64 {
+	return 0.2
+}
+
+// GameScene — игровой процесс
+type GameScene struct {
+	g  *Game
+	ui *ui.Root
+}
+
+func NewGameScene(g *Game) *GameScene {
+	return &GameScene{g: g, ui: g.buildGameUI()}
+}
+
+func (s *GameScene) Enter() {
+	s.g.enterWorld()
+}
+
+// Exit сохраняет игру в кольцо автосохранений при выходе в меню, но не
+// когда игру сменяет загруженное сохранение
+func (s *GameScene) Exit() {
+	if !s.g.loading {
+		s.g.autosave()
+	}
+	s.g.session = nil
+}
+
+// OnResume продолжает игру, когда закрываются настройки или список
+// слотов: пока они были сверху, мир и время игры стояли
+func (s *GameScene) OnResume() {
+	s.g.resumeWorld()
+}
+
+func (s *GameScene) Relayout() {
+	s.ui.Layout()
+}
+
+func (s *GameScene) Update() error {
+	s.g.updateGame()
+	return nil
+}
+
+func (s *GameScene) Draw(screen *ebiten.Image) {
+	s.g.DrawGame(screen, s.ui)
+}
+
+// HandlesBack — пока открыта надпись, Back закрывает её, а не игру
+func (s *GameScene) HandlesBack() bool {
+	return s.g.world != nil && s.g.world.message != ""
+}
+
+// Opaque — карта закрывает весь экран, фон меню под ней не нужен
+func (s *GameScene) Opaque() bool {
+	return true
+}
+
+// Playlist — в игре звучит музыка исследования
+func (s *GameScene) Playlist() string {
+	return PlaylistExploration
+}
+
+// MusicLevel — во время игры музыка приглушена до 20%
+func (s *GameScene) MusicLevel() float64 {
+	return 0.2
+}
+
+// SaveSlotsScene — список слотов: загрузка из главного меню или сохранение
+// во время игры
+type SaveSlotsScene struct {
+	g    *Game
+	mode slotsMode
+	ui   *ui.Root
+
+	// thumbnail — кадр игры на момент открытия экрана сохранения
+	thumbnail []byte
+	// thumbnails — миниатюры карточек; освобождаются при закрытии
+	thumbnails []*ebiten.Image
+}
+
+// NewLoadGameScene открывает список сохранений для загрузки
+func NewLoadGameScene(g *Game) *SaveSlotsScene {
+	s := &SaveSlotsScene{g: g, mode: slotsLoad}
+	s.ui = g.buildSaveSlotsUI(s)
+	return s
+}
+
+// NewSaveGameScene открывает ручные слоты для сохранения текущей игры
+func NewSaveGameScene(g *Game) *SaveSlotsScene {
+	s := &SaveSlotsScene{g: g, mode: slotsSave, thumbnail: g.captureThumbnail()}
+	s.ui = g.buildSaveSlotsUI(s)
+	return s
+}
+
+// saveTo сохраняет игру в слот и возвращает к игре
+func (s *SaveSlotsScene) saveTo(slot save.Slot) {
+	if s.g.saveGame(slot, s.thumbnail) {
+		s.g.PopScene()
+	}
+}
+
+func (s *SaveSlotsScene) Enter() {
+	s.ui.Reset()
+}
+
+func (s *SaveSlotsScene) Exit() {
+	for _, img := range s.thumbnails {
+		img.Dispose()
+	}
+	s.thumbnails = nil
+}
+
+func (s *SaveSlotsScene) OnResume() {
+	s.ui.Reset()
+}
+
+func (s *SaveSlotsScene) Relayout() {
+	s.ui.Layout()
+}
+
+func (s *SaveSlotsScene) Update() error {
+	s.g.updateGlow()
+	s.g.updateWidgets(s.ui)
+	return nil
+}
+
+func (s *SaveSlotsScene) Draw(screen *ebiten.Image) {
+	s.g.DrawSaveSlots(screen, s.ui)
+}
+
+// Playlist — список слотов не меняет музыку сцены, поверх которой открыт
+func (s *SaveSlotsScene) Playlist() string {
+	return ""
+}
+
+// MusicLevel — музыка приглушена, как в настройках
+func (s *SaveSlotsScene) MusicLevel() float64 {
+	return 0.2
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"aethelgard/internal/sfx"
+	"io"
+	"log"
+	"math"
+)
+
+// SoundCuesFile — описания звуковых реплик в ресурсах
+const SoundCuesFile = "sfx/cues.json"
+
+// Реплики интерфейса
+const (
+	CueMenuMove    = "menu_move"
+	CueMenuConfirm = "menu_confirm"
+	CueMenuBack    = "menu_back"
+	CueSliderTick  = "slider_tick"
+)
+
+// Реплики мира
+const (
+	CueFootstep = "footstep"
+)
+
+// SliderTickStep — ползунок щёлкает, проходя каждые 5%
+const SliderTickStep = 0.05
+
+// loadSounds декодирует звуковые эффекты из файла реплик
+func (g *Game) loadSounds() {
+	g.sfx = sfx.New(g.audioContext, g.mixer, func(name string) (io.ReadCloser, error) {
+		stream, err := g.decodeAudio(name)
+		if err != nil {
+			return nil, err
+		}
+		return stream, nil
+	})
+	if err := g.sfx.Load(g.assets, SoundCuesFile); err != nil {
+		log.Printf("Warning: Failed to load sound cues: %v", err)
+	}
+}
+
+// playSound проигрывает реплику
+func (g *Game) playSound(cue string) {
+	if g.sfx != nil {
+		g.sfx.Play(cue)
+	}
+}
+
+// sliderTick щёлкает, если значение ползунка перешло через деление
+func (g *Game) sliderTick(from, to float64) {
+	if math.Floor(from/SliderTickStep+1e-9) != math.Floor(to/SliderTickStep+1e-9) {
+		g.playSound(CueSliderTick)
+	}
+}
+
+// goBack закрывает верхнюю сцену со звуком возврата
+func (g *Game) goBack() {
+	g.playSound(CueMenuBack)
+	g.PopScene()
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"aethelgard/internal/anim"
+	"aethelgard/internal/ecs"
+	"aethelgard/internal/entities"
+	"aethelgard/internal/sprite"
+	"image"
+	"log"
+	"math"
+)
+
+// SpriteFiles — описания спрайтов персонажей; их листы упаковываются
+// в один атлас
+var SpriteFiles = []string{
+	"sprites/hero.json",
+	"sprites/villager.json",
+}
+
+// Спрайты героя и жителей по умолчанию
+const (
+	HeroSprite = "hero"
+	NPCSprite  = "villager"
+)
+
+// События кадров анимации, на которые отзывается игра
+const (
+	EventFootstep = "footstep"
+	EventHit      = "hit"
+)
+
+// HitTrauma — тряска камеры на кадре удара
+const HitTrauma = 0.2
+
+// loadSprites упаковывает спрайты в атлас и отдаёт их анимации миру
+// сущностей. Без атласа сущности рисуются фигурами.
+func (g *Game) loadSprites(w *world) {
+	atlas, err := sprite.Load(g.assets, SpriteFiles...)
+	if err != nil {
+		log.Printf("Warning: failed to load sprites: %v", err)
+		atlas = nil
+	}
+	w.sprites = atlas
+
+	lib := anim.Library{}
+	if atlas != nil {
+		lib = atlas.Library()
+	}
+	ecs.SetResource(w.ecs, &lib)
+
+	events := &entities.FrameEvents{}
+	events.On(EventFootstep, g.onFootstep)
+	events.On(EventHit, g.onHit)
+	ecs.SetResource(w.ecs, events)
+}
+
+// animate даёт сущности аниматор спрайта. Если спрайт не загрузился,
+// аниматор всё равно сохранится с его именем и оживёт, когда спрайт найдётся.
+func (g *Game) animate(e ecs.Entity, name string) {
+	c := g.world.c
+	if s := g.sheet(name); s != nil {
+		c.Animator.Add(e, anim.New(s.Anim))
+		return
+	}
+	c.Animator.Add(e, anim.Animator{Set: name})
+}
+
+func (g *Game) sheet(name string) *sprite.Sheet {
+	if g.world.sprites == nil {
+		return nil
+	}
+	return g.world.sprites.Sheets[name]
+}
+
+// spriteFrame возвращает текущий кадр аниматора или nil
+func (g *Game) spriteFrame(a *anim.Animator) (*sprite.Frame, bool) {
+	s := g.sheet(a.Set)
+	if s == nil {
+		return nil, false
+	}
+	i, flipX, ok := a.Current(s.Anim)
+	if !ok {
+		return nil, false
+	}
+	return &s.Frames[i], flipX
+}
+
+// onFootstep топает: герой слышен всегда, остальные — если они в кадре
+func (g *Game) onFootstep(e ecs.Entity) {
+	w := g.world
+	if !w.c.Hero.Has(e) {
+		pos := w.c.Position.Get(e)
+		if pos == nil || !image.Pt(int(math.Round(pos.X)), int(math.Round(pos.Y))).In(w.camera.View()) {
+			return
+		}
+	}
+	g.playSound(CueFootstep)
+}
+
+// onHit встряхивает камеру на кадре удара героя
+func (g *Game) onHit(e ecs.Entity) {
+	if g.world.c.Hero.Has(e) {
+		g.world.camera.Shake.Add(HitTrauma)
+	}
+}
@@ -1,6 +1,19 @@
 package game
 
 import (
+	"aethelgard/internal/fonts"
+	"aethelgard/internal/i18n"
+	"aethelgard/internal/input"
+	"aethelgard/internal/mixer"
+	"aethelgard/internal/music"
+	"aethelgard/internal/save"
+	"aethelgard/internal/settings"
+	"aethelgard/internal/sfx"
+	"aethelgard/internal/ui"
+	"io/fs"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"golang.org/x/image/font"
 )
@@ -11,29 +24,70 @@ type MenuItem struct {
 }
 
 type Game struct {
-	// Состояние и язык
-	state    int
-	language int
+	// Ресурсы: встроенные, каталог на диске и моды
+	assets fs.FS
+
+	// Стек сцен
+	scenes sceneStack
+
+	// Язык и таблицы строк
+	language   string
+	languages  []i18n.Language
+	locales    *i18n.Bundle
+	translator *i18n.Translator
 
 	// Меню
-	menuItems     []MenuItem
-	selectedIndex int
+	menuItems []MenuItem
 
-	// Шрифты
+	// Шрифты и тема интерфейса
+	fonts     *fonts.Manager
 	titleFont font.Face
 	menuFont  font.Face
+	uiTheme   *ui.Theme
 
 	// Эффекты
 	glowIntensity float64
 	glowDirection float64
-	keyPressed    bool
+
+	// Ввод
+	input *input.Manager
+
+	// Путь к файлу пользовательских настроек
+	settingsPath string
 
 	// Аудио
-	audioContext     *audio.Context
-	bgMusic          *audio.Player
-	masterVolume     float64
-	isDraggingVolume bool
+	audioContext *audio.Context
+	music        *music.Director
+	sfx          *sfx.Service
+	mixer        *mixer.Mixer
+
+	// Экран: настройки, размер окна в пикселях, логический размер холста,
+	// холст кадра, его положение в окне, ограничение кадров и размер
+	// экрана, на котором нарисован последний кадр
+	display            settings.Display
+	outsideW, outsideH int
+	screenW, screenH   int
+	canvas             *ebiten.Image
+	view               viewport
+	pacer              framePacer
+	drawnW, drawnH     int
+
+	// Сохранения и состояние текущей игры (nil вне игры); время игры
+	// последнего автосохранения; сообщение о сохранении. loading — сцены
+	// снимаются ради загрузки сохранения, и брошенная игра не автосохраняется.
+	saves        *save.Store
+	session      *save.State
+	lastAutosave time.Duration
+	loading      bool
+	notice       string
+	noticeUntil  time.Time
+
+	// Карта мира, загружается при первом входе в игру
+	world *world
 
 	// Видео
 	videoPlayer *VideoPlayer
+
+	// Отладочный оверлей (F3)
+	showDebug bool
 }
@@ -1,189 +1,159 @@
 package game
 
 import (
-	"log"
+	"aethelgard/internal/input"
+	"aethelgard/internal/save"
+	"aethelgard/internal/ui"
 	"os"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
 )
 
 func (g *Game) Update() error {
+	g.updateScreen()
+	g.input.Update()
+
 	if g.videoPlayer != nil {
 		g.videoPlayer.Update()
 	}
 
-	if g.bgMusic != nil && g.state == MenuState {
-		if !g.bgMusic.IsPlaying() {
-			log.Println("Music stopped unexpectedly, restarting...")
-			g.bgMusic.Rewind()
-			g.bgMusic.Play()
-		}
+	if g.input.JustPressed(input.DebugOverlay) {
+		g.showDebug = !g.showDebug
 	}
 
-	escPressed := ebiten.IsKeyPressed(ebiten.KeyEscape)
-
-	if escPressed && !g.keyPressed {
-		g.keyPressed = true
+	// Музыка следует за верхней сценой стека
+	g.updateMusicState()
+	g.music.Update(time.Second / time.Duration(ebiten.TPS()))
+	g.mixer.Update()
 
-		if g.state == GameState {
-			g.state = MenuState
-		} else if g.state == SettingsState {
-			g.state = MenuState
-		} else {
+	if g.input.JustPressed(input.Back) && !g.topHandlesBack() {
+		// Back закрывает верхнюю сцену; закрытие корневой сцены — выход из игры
+		g.goBack()
+		if g.scenes.empty() {
 			os.Exit(0)
 		}
-
-		g.updateMusicState()
+		return nil
 	}
 
-	if g.state == MenuState {
-		g.glowIntensity += g.glowDirection
-		if g.glowIntensity > 1.0 {
-			g.glowIntensity = 1.0
-			g.glowDirection = -0.02
-		} else if g.glowIntensity < 0.3 {
-			g.glowIntensity = 0.3
-			g.glowDirection = 0.02
-		}
-
-		upPressed := ebiten.IsKeyPressed(ebiten.KeyArrowUp) || ebiten.IsKeyPressed(ebiten.KeyW)
-		downPressed := ebiten.IsKeyPressed(ebiten.KeyArrowDown) || ebiten.IsKeyPressed(ebiten.KeyS)
-		enterPressed := ebiten.IsKeyPressed(ebiten.KeyEnter) || ebiten.IsKeyPressed(ebiten.KeySpace)
-
-		if (upPressed || downPressed || enterPressed) && !g.keyPressed {
-			g.keyPressed = true
-
-			if upPressed {
-				g.selectedIndex--
-				if g.selectedIndex < 0 {
-					g.selectedIndex = len(g.menuItems) - 1
-				}
-			}
-
-			if downPressed {
-				g.selectedIndex++
-				if g.selectedIndex >= len(g.menuItems) {
-					g.selectedIndex = 0
-				}
-			}
-
-			if enterPressed {
-				g.handleMenuAction(g.selectedIndex)
-				g.keyPressed = false
-			}
-		}
-
-		if !upPressed && !downPressed && !enterPressed && !escPressed {
-			g.keyPressed = false
-		}
-
-		mouseX, mouseY := ebiten.CursorPosition()
-		menuX := 80
-		startY := 320
-
-		for i := range g.menuItems {
-			itemY := startY + i*60
-			bounds := text.BoundString(g.menuFont, g.getText(g.menuItems[i].label))
-			itemHeight := bounds.Max.Y - bounds.Min.Y
-
-			if mouseX >= menuX && mouseX <= menuX+300 &&
-				mouseY >= itemY-itemHeight && mouseY <= itemY+10 {
-				g.selectedIndex = i
-
-				if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-					g.handleMenuAction(i)
-				}
-			}
-		}
+	if scene := g.CurrentScene(); scene != nil {
+		return scene.Update()
 	}
 
-	if g.state == SettingsState {
-		g.glowIntensity += g.glowDirection
-		if g.glowIntensity > 1.0 {
-			g.glowIntensity = 1.0
-			g.glowDirection = -0.02
-		} else if g.glowIntensity < 0.3 {
-			g.glowIntensity = 0.3
-			g.glowDirection = 0.02
-		}
+	return nil
+}
 
-		mouseX, mouseY := ebiten.CursorPosition()
-		mouseClicked := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+// topHandlesBack сообщает, что верхняя сцена обрабатывает Back сама
+func (g *Game) topHandlesBack() bool {
+	b, ok := g.CurrentScene().(backScene)
+	return ok && b.HandlesBack()
+}
 
-		russianButtonX := ScreenWidth/2 - 130
-		russianButtonY := 230
-		russianButtonWidth := 120
-		russianButtonHeight := 45
+// pointer собирает состояние указателя для виджетов
+func (g *Game) pointer() ui.Pointer {
+	x, y := g.view.toCanvas(ebiten.CursorPosition())
+	return ui.Pointer{
+		X:        x,
+		Y:        y,
+		Down:     g.input.Pressed(input.PointerPress),
+		Pressed:  g.input.JustPressed(input.PointerPress),
+		Released: g.input.JustReleased(input.PointerPress),
+	}
+}
 
-		if mouseX >= russianButtonX && mouseX <= russianButtonX+russianButtonWidth &&
-			mouseY >= russianButtonY && mouseY <= russianButtonY+russianButtonHeight {
-			if mouseClicked && !g.keyPressed {
-				g.keyPressed = true
-				g.language = LanguageRussian
-			}
-		}
+// updateFocusNavigation переводит фокус виджетов действиями меню
+func (g *Game) updateFocusNavigation(root *ui.Root) {
+	if g.input.Repeated(input.MenuUp) {
+		root.FocusPrev()
+	}
+	if g.input.Repeated(input.MenuDown) {
+		root.FocusNext()
+	}
+	if g.input.Repeated(input.MenuLeft) {
+		root.Adjust(-0.05)
+	}
+	if g.input.Repeated(input.MenuRight) {
+		root.Adjust(0.05)
+	}
+	if g.input.Repeated(input.ValueDecrease) {
+		root.Adjust(-0.1)
+	}
+	if g.input.Repeated(input.ValueIncrease) {
+		root.Adjust(0.1)
+	}
+	if g.input.JustPressed(input.Confirm) {
+		root.Activate()
+	}
+}
 
-		englishButtonX := ScreenWidth/2 + 10
-		englishButtonY := 230
-		englishButtonWidth := 120
-		englishButtonHeight := 45
-
-		if mouseX >= englishButtonX && mouseX <= englishButtonX+englishButtonWidth &&
-			mouseY >= englishButtonY && mouseY <= englishButtonY+englishButtonHeight {
-			if mouseClicked && !g.keyPressed {
-				g.keyPressed = true
-				g.language = LanguageEnglish
-			}
+// prompt собирает подсказку из значков действий для последнего использованного устройства
+func (g *Game) prompt(key string, actions ...input.Action) ui.Prompt {
+	p := ui.Prompt{Key: key}
+	for _, a := range actions {
+		glyph, ok := g.input.Glyph(a)
+		if !ok {
+			continue
 		}
-
-		backButtonX := ScreenWidth/2 - 100
-		backButtonY := 480
-		backButtonWidth := 200
-		backButtonHeight := 50
-
-		if mouseX >= backButtonX && mouseX <= backButtonX+backButtonWidth &&
-			mouseY >= backButtonY && mouseY <= backButtonY+backButtonHeight {
-			if mouseClicked && !g.keyPressed {
-				g.keyPressed = true
-				g.state = MenuState
-			}
+		if p.Glyph != "" {
+			p.Glyph += "/"
 		}
+		p.Glyph += glyph.Label
+		p.Gamepad = glyph.Gamepad
+	}
+	return p
+}
 
-		volumeSliderX := ScreenWidth/2 - 150
-		volumeSliderY := 360
-		volumeSliderWidth := 300
-		volumeSliderHeight := 18
+// updateGlow пульсирует подсветку выбранного элемента
+func (g *Game) updateGlow() {
+	g.glowIntensity += g.glowDirection
+	if g.glowIntensity > 1.0 {
+		g.glowIntensity = 1.0
+		g.glowDirection = -0.02
+	} else if g.glowIntensity < 0.3 {
+		g.glowIntensity = 0.3
+		g.glowDirection = 0.02
+	}
+	g.uiTheme.Glow = g.glowIntensity
+}
 
-		if mouseY >= volumeSliderY && mouseY <= volumeSliderY+volumeSliderHeight &&
-			mouseX >= volumeSliderX && mouseX <= volumeSliderX+volumeSliderWidth {
-			if mouseClicked {
-				g.isDraggingVolume = true
-			}
-		}
+// updateMenu обрабатывает ввод в главном меню
+func (g *Game) updateMenu(root *ui.Root) {
+	g.updateGlow()
+	g.updateWidgets(root)
+}
 
-		if g.isDraggingVolume {
-			if mouseClicked {
-				relativeX := float64(mouseX - volumeSliderX)
-				g.masterVolume = relativeX / float64(volumeSliderWidth)
-
-				if g.masterVolume < 0 {
-					g.masterVolume = 0
-				}
-				if g.masterVolume > 1 {
-					g.masterVolume = 1
-				}
-
-				g.updateMusicState()
-			} else {
-				g.isDraggingVolume = false
-			}
-		}
+// updateSettings обрабатывает ввод на экране настроек
+func (g *Game) updateSettings(root *ui.Root) {
+	g.updateGlow()
+	g.updateWidgets(root)
+}
 
-		if !mouseClicked && !escPressed {
-			g.keyPressed = false
-		}
+// updateWidgets передаёт ввод виджетам и щёлкает при смене фокуса
+// с клавиатуры, геймпада или мышью
+func (g *Game) updateWidgets(root *ui.Root) {
+	focus := root.Focus()
+	g.updateFocusNavigation(root)
+	root.Update(g.pointer())
+	if focus >= 0 && root.Focus() != focus {
+		g.playSound(CueMenuMove)
 	}
+}
 
-	return nil
+// updateGame обрабатывает ввод во время игры
+func (g *Game) updateGame() {
+	g.updateSession()
+	g.updateWorld()
+
+	switch {
+	case g.input.JustPressed(input.QuickSave):
+		g.saveGame(save.QuickSlot, g.captureThumbnail())
+	case g.input.JustPressed(input.QuickLoad):
+		g.loadGame(save.QuickSlot)
+	case g.input.JustPressed(input.SaveMenu):
+		g.playSound(CueMenuConfirm)
+		g.PushScene(NewSaveGameScene(g))
+	case g.input.JustPressed(input.Pause):
+		g.playSound(CueMenuConfirm)
+		g.PushScene(NewSettingsScene(g))
+	}
 }
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"aethelgard/internal/input"
+	"aethelgard/internal/mixer"
+	"aethelgard/internal/settings"
+	"errors"
+	"log"
+)
+
+// loadSettings читает пользовательские настройки; если файл повреждён
+// или недоступен, остаются значения по умолчанию
+func (g *Game) loadSettings() {
+	path, err := settings.Path()
+	if err != nil {
+		log.Printf("Warning: settings location unavailable, using defaults: %v", err)
+		g.applySettings(settings.Defaults())
+		return
+	}
+	g.settingsPath = path
+
+	s, err := settings.Load(path)
+	switch {
+	case errors.Is(err, settings.ErrTooNew):
+		// Настройки новой версии игры читаем, но не перезаписываем
+		log.Printf("Warning: %v; settings changes will not be saved", err)
+		g.settingsPath = ""
+	case err != nil:
+		log.Printf("Warning: failed to load settings, using defaults: %v", err)
+	default:
+		log.Printf("Settings loaded from %s", path)
+	}
+	g.applySettings(s)
+}
+
+// applySettings переносит настройки в состояние игры
+func (g *Game) applySettings(s settings.Settings) {
+	for name, b := range s.Audio.Buses {
+		g.mixer.SetVolume(mixer.Bus(name), b.Volume)
+		g.mixer.SetMuted(mixer.Bus(name), b.Muted)
+	}
+
+	g.applyDisplay(s.Display)
+
+	g.setLanguage(s.Language)
+
+	bindings := input.DefaultBindings()
+	for action, list := range s.Controls {
+		parsed := make([]input.Binding, 0, len(list))
+		for _, text := range list {
+			b, err := input.ParseBinding(text)
+			if err != nil {
+				log.Printf("Warning: ignoring binding for %s: %v", action, err)
+				continue
+			}
+			parsed = append(parsed, b)
+		}
+		bindings[input.Action(action)] = parsed
+	}
+	g.input.SetBindings(bindings)
+}
+
+// currentSettings собирает настройки из состояния игры
+func (g *Game) currentSettings() settings.Settings {
+	s := settings.Defaults()
+	for _, bus := range mixer.Buses {
+		s.Audio.Buses[string(bus)] = settings.Bus{
+			Volume: g.mixer.Volume(bus),
+			Muted:  g.mixer.Muted(bus),
+		}
+	}
+
+	s.Display = g.display
+	s.Language = g.language
+
+	s.Controls = make(map[string][]string)
+	for action, list := range g.input.Bindings() {
+		texts := make([]string, len(list))
+		for i, b := range list {
+			texts[i] = b.String()
+		}
+		s.Controls[string(action)] = texts
+	}
+	return s
+}
+
+// saveSettings записывает настройки на диск
+func (g *Game) saveSettings() {
+	if g.settingsPath == "" {
+		return
+	}
+	if err := settings.Save(g.settingsPath, g.currentSettings()); err != nil {
+		log.Printf("Warning: failed to save settings: %v", err)
+		return
+	}
+	log.Printf("Settings saved to %s", g.settingsPath)
+}
@@ -1,121 +1,130 @@
 package game
 
 import (
-	"fmt"
+	"aethelgard/internal/video"
+	"io/fs"
 	"log"
-	"os"
-	"path/filepath"
-	"sort"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
 
-// VideoPlayer — стриминговый плеер: в памяти только один кадр
+// VideoPlayer — плеер фонового видео: кадры декодируются в фоновой горутине,
+// а в Update готовые пиксели только загружаются в одну текстуру
 type VideoPlayer struct {
-	framePaths   []string
-	currentIndex int
+	decoder      *video.Decoder
+	ticks        int64 // тиков с начала воспроизведения
+	seq          int64 // номер кадра, который должен быть на экране
+	shown        int64 // номер кадра, загруженного в currentFrame
 	frameCount   int
-	frameDelay   int
-	frameTimer   int
-	fps          int
+	fps          float64
+	once         bool
 	currentFrame *ebiten.Image
-}
-
-func NewVideoPlayer(videoPath string, targetFPS int) (*VideoPlayer, error) {
-	videoDir := "assets/video_frames"
 
-	if _, err := os.Stat(videoDir); os.IsNotExist(err) {
-		log.Printf("Video frames directory not found, path: %s", videoDir)
-		return nil, fmt.Errorf("video frames directory not found")
-	}
+	// clock — внешние часы воспроизведения, например позиция звуковой дорожки;
+	// без них время считается по тикам
+	clock func() time.Duration
+}
 
-	files, err := os.ReadDir(videoDir)
+// NewVideoPlayer открывает ролик videoPath: каталог кадров или Motion JPEG AVI.
+// Частота кадров берётся из контейнера, а если её там нет — defaultFPS.
+func NewVideoPlayer(fsys fs.FS, videoPath string, defaultFPS float64, opts video.Options) (*VideoPlayer, error) {
+	src, err := video.Open(fsys, videoPath)
 	if err != nil {
 		return nil, err
 	}
 
-	sort.Slice(files, func(i, j int) bool {
-		return files[i].Name() < files[j].Name()
-	})
-
-	var framePaths []string
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-		ext := filepath.Ext(file.Name())
-		if ext != ".png" && ext != ".jpg" && ext != ".jpeg" {
-			continue
-		}
-		fullPath := filepath.Join(videoDir, file.Name())
-		framePaths = append(framePaths, fullPath)
-	}
-
-	if len(framePaths) == 0 {
-		return nil, fmt.Errorf("no video frames found in %s", videoDir)
-	}
-
-	log.Printf("VideoPlayer: found %d frame files", len(framePaths))
-
-	frameDelay := 1
-	if targetFPS > 0 {
-		frameDelay = int(ebiten.TPS() / targetFPS)
-		if frameDelay <= 0 {
-			frameDelay = 1
-		}
+	fps := src.FrameRate()
+	if fps <= 0 {
+		fps = defaultFPS
 	}
 
-	firstImg, _, err := ebitenutil.NewImageFromFile(framePaths[0])
+	decoder, err := video.NewDecoder(src, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load first frame: %w", err)
+		src.Close()
+		return nil, err
 	}
+	w, h := decoder.Size()
 
 	v := &VideoPlayer{
-		framePaths:   framePaths,
-		currentIndex: 0,
-		frameCount:   len(framePaths),
-		frameDelay:   frameDelay,
-		frameTimer:   0,
-		fps:          targetFPS,
-		currentFrame: firstImg,
+		decoder:      decoder,
+		shown:        -1,
+		frameCount:   decoder.FrameCount(),
+		fps:          fps,
+		once:         opts.Once,
+		currentFrame: ebiten.NewImage(w, h),
 	}
 
-	log.Printf("VideoPlayer initialized: %d frames, targetFPS=%d, frameDelay=%d",
-		v.frameCount, v.fps, v.frameDelay)
+	stats := decoder.Stats()
+	log.Printf("VideoPlayer initialized: %s, %d frames %dx%d, fps=%.2f, buffer=%d frames",
+		videoPath, v.frameCount, w, h, v.fps, stats.Capacity)
 
 	return v, nil
 }
 
+// NewStaticVideoPlayer показывает одну картинку вместо видео
+func NewStaticVideoPlayer(img *ebiten.Image) *VideoPlayer {
+	return &VideoPlayer{
+		frameCount:   1,
+		currentFrame: img,
+	}
+}
+
 func (v *VideoPlayer) Update() {
-	if v.frameCount == 0 || v.currentFrame == nil {
+	if v.decoder == nil {
 		return
 	}
 
-	v.frameTimer++
-	if v.frameTimer < v.frameDelay {
+	// Номер кадра считается от времени, а не прибавляется раз в N тиков,
+	// поэтому 25 и 30 кадров в секунду одинаково точны при 60 TPS
+	v.ticks++
+	if v.fps > 0 {
+		v.seq = int64(v.Position().Seconds() * v.fps)
+	}
+	if v.once && v.seq >= int64(v.frameCount) {
 		return
 	}
-	v.frameTimer = 0
 
-	v.currentIndex++
-	if v.currentIndex >= v.frameCount {
-		v.currentIndex = 0
+	// Пока нужный кадр не готов, показывается самый свежий из готовых
+	if v.shown != v.seq {
+		v.upload(v.seq)
 	}
+}
 
-	if v.currentFrame != nil {
-		v.currentFrame.Dispose()
-		v.currentFrame = nil
+func (v *VideoPlayer) upload(seq int64) {
+	frame, ok := v.decoder.Acquire(seq)
+	if !ok {
+		return
 	}
+	v.currentFrame.WritePixels(frame.Image.Pix)
+	v.decoder.Release(frame)
+	v.shown = frame.Seq
+}
 
-	path := v.framePaths[v.currentIndex]
-	img, _, err := ebitenutil.NewImageFromFile(path)
-	if err != nil {
-		log.Printf("VideoPlayer: failed to load frame %s: %v", path, err)
-		return
+// SetClock привязывает кадры к внешним часам, чтобы видео не расходилось со звуком
+func (v *VideoPlayer) SetClock(clock func() time.Duration) {
+	v.clock = clock
+}
+
+// Position возвращает текущее время воспроизведения
+func (v *VideoPlayer) Position() time.Duration {
+	if v.clock != nil {
+		return v.clock()
 	}
+	return time.Duration(v.ticks) * time.Second / time.Duration(ebiten.TPS())
+}
+
+// Duration возвращает длительность ролика; 0, если частота кадров неизвестна
+func (v *VideoPlayer) Duration() time.Duration {
+	if v.fps <= 0 {
+		return 0
+	}
+	return time.Duration(float64(v.frameCount) / v.fps * float64(time.Second))
+}
 
-	v.currentFrame = img
+// Finished сообщает, что ролик, открытый с Once, доигран до конца
+func (v *VideoPlayer) Finished() bool {
+	return v.once && v.seq >= int64(v.frameCount)
 }
 
 func (v *VideoPlayer) CurrentFrame() *ebiten.Image {
@@ -123,6 +132,10 @@ func (v *VideoPlayer) CurrentFrame() *ebiten.Image {
 }
 
 func (v *VideoPlayer) Close() {
+	if v.decoder != nil {
+		v.decoder.Close()
+		v.decoder = nil
+	}
 	if v.currentFrame != nil {
 		v.currentFrame.Dispose()
 		v.currentFrame = nil
@@ -133,6 +146,14 @@ func (v *VideoPlayer) FrameCount() int {
 	return v.frameCount
 }
 
-func (v *VideoPlayer) FPS() int {
+func (v *VideoPlayer) FPS() float64 {
 	return v.fps
 }
+
+// Stats возвращает состояние декодера; у статичной картинки оно нулевое
+func (v *VideoPlayer) Stats() video.Stats {
+	if v.decoder == nil {
+		return video.Stats{}
+	}
+	return v.decoder.Stats()
+}
@@ -0,0 +1,280 @@
+package game
+
+import (
+	"aethelgard/internal/camera"
+	"aethelgard/internal/ecs"
+	"aethelgard/internal/entities"
+	"aethelgard/internal/input"
+	"aethelgard/internal/physics"
+	"aethelgard/internal/sprite"
+	"aethelgard/internal/tilemap"
+	"aethelgard/internal/ui"
+	"image"
+	"image/color"
+	"log"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// WorldMap — карта мира в ресурсах
+const WorldMap = "maps/world.tmj"
+
+// SpawnObject — объект карты, у которого начинается игра
+const SpawnObject = "spawn"
+
+// PropLocation — свойство карты с ключом локализации локации
+const PropLocation = "location"
+
+// Миникарта: масштаб и окно в правом нижнем углу (в пикселях макета)
+const (
+	MinimapZoom   = 0.125
+	minimapWidth  = 240
+	minimapHeight = 150
+)
+
+// world — загруженная карта, её отрисовщик, камеры, мир столкновений,
+// атлас спрайтов и сущности с системами
+type world struct {
+	m        *tilemap.Map
+	renderer *tilemap.Renderer
+	sprites  *sprite.Atlas
+
+	camera  *camera.Camera
+	minimap *camera.Camera
+
+	physics *physics.World
+	clock   *physics.Clock
+
+	ecs     *ecs.World
+	c       *entities.Components
+	systems *ecs.Scheduler
+
+	// message — ключ открытой надписи или реплики
+	message string
+}
+
+// loadWorld загружает карту мира при первом входе в игру
+func (g *Game) loadWorld() bool {
+	if g.world != nil {
+		return true
+	}
+
+	m, err := tilemap.Load(g.assets, WorldMap)
+	if err != nil {
+		log.Printf("Warning: failed to load world map: %v", err)
+		return false
+	}
+	r, err := tilemap.NewRenderer(g.assets, m)
+	if err != nil {
+		log.Printf("Warning: failed to load world tiles: %v", err)
+		return false
+	}
+
+	w := &world{
+		m:        m,
+		renderer: r,
+		camera:   camera.New(image.Rect(0, 0, g.screenW, g.screenH)),
+		minimap:  camera.New(image.Rectangle{}),
+	}
+	w.camera.Bounds = m.PixelBounds()
+	w.camera.DeadZoneW, w.camera.DeadZoneH = 96, 64
+	w.minimap.Bounds = m.PixelBounds()
+	w.minimap.SetZoom(MinimapZoom)
+	w.minimap.Shake.Scale = 0
+
+	w.physics = &physics.World{
+		Grid:       m,
+		TileW:      float64(m.TileWidth),
+		TileH:      float64(m.TileHeight),
+		CornerSlop: 8,
+	}
+	w.clock = physics.NewClock(physics.DefaultStep)
+
+	w.ecs = ecs.NewWorld()
+	w.c = entities.Register(w.ecs)
+	w.systems = &ecs.Scheduler{}
+	entities.AddSystems(w.systems, w.c)
+	w.systems.Add(ecs.PhaseRender, "actors", g.drawActors)
+	ecs.SetResource(w.ecs, w.physics)
+	ecs.SetResource(w.ecs, &entities.Control{})
+	ecs.SetResource(w.ecs, &renderFrame{})
+	g.loadSprites(w)
+
+	g.world = w
+	return true
+}
+
+// enterWorld заселяет мир сущностями из сохранения, а в новой игре или
+// в сохранении без сущностей — с карты, и записывает локацию карты в сессию
+func (g *Game) enterWorld() {
+	if !g.loadWorld() {
+		return
+	}
+	w := g.world
+	g.restoreEntities()
+	if loc := w.m.Properties[PropLocation]; loc != "" && g.session != nil {
+		g.session.Location = loc
+	}
+	w.message = ""
+	w.clock.Reset()
+
+	x, y := g.heroPosition()
+	g.layoutCameras()
+	w.camera.Shake.Trauma = 0
+	w.camera.Snap(x, y)
+	w.minimap.Snap(x, y)
+	g.preloadChunks()
+}
+
+// resumeWorld продолжает мир после паузы: недосчитанная доля шага часов
+// отбрасывается, а камеры встают под холст, который могли изменить
+// настройки экрана
+func (g *Game) resumeWorld() {
+	w := g.world
+	if w == nil {
+		return
+	}
+	w.clock.Reset()
+	g.layoutCameras()
+	g.preloadChunks()
+}
+
+// layoutCameras подгоняет окна камер под размер холста и масштаб интерфейса
+func (g *Game) layoutCameras() {
+	w := g.world
+	w.camera.Viewport = image.Rect(0, 0, g.screenW, g.screenH)
+	w.minimap.Viewport = ui.Box{
+		At:    ui.At(ui.BottomRight, -40, -40),
+		W:     minimapWidth,
+		H:     minimapHeight,
+		Pivot: ui.BottomRight,
+	}.Resolve(g.uiTheme)
+}
+
+// updateWorld читает ввод, просчитывает системы мира с фиксированным шагом,
+// ведёт камеры за героем и подгружает чанки
+func (g *Game) updateWorld() {
+	w := g.world
+	if w == nil {
+		return
+	}
+
+	// Пока открыта надпись, герой стоит; Confirm и Back закрывают её
+	ctl := ecs.Resource[entities.Control](w.ecs)
+	var ix, iy float64
+	if w.message != "" {
+		if g.input.JustPressed(input.Confirm) || g.input.JustPressed(input.Back) {
+			w.message = ""
+			g.playSound(CueMenuBack)
+		}
+	} else {
+		if g.input.Pressed(input.MenuLeft) {
+			ix--
+		}
+		if g.input.Pressed(input.MenuRight) {
+			ix++
+		}
+		if g.input.Pressed(input.MenuUp) {
+			iy--
+		}
+		if g.input.Pressed(input.MenuDown) {
+			iy++
+		}
+		if g.input.JustPressed(input.Confirm) {
+			g.interact()
+		}
+		if g.input.JustPressed(input.Attack) {
+			ctl.Attack = true
+		}
+	}
+
+	ctl.X, ctl.Y = ix, iy
+	steps := w.clock.Advance(time.Second / time.Duration(ebiten.TPS()))
+	for i := 0; i < steps; i++ {
+		w.systems.Update(w.ecs, w.clock.Seconds())
+	}
+
+	if g.input.JustPressed(input.ZoomIn) {
+		w.camera.ZoomStep(1)
+	}
+	if g.input.JustPressed(input.ZoomOut) {
+		w.camera.ZoomStep(-1)
+	}
+
+	x, y := g.heroPosition()
+	var vx, vy float64
+	if hero := w.c.Walker.Get(w.c.FindHero()); hero != nil {
+		vx, vy = hero.VX, hero.VY
+	}
+	g.layoutCameras()
+	w.camera.Follow(x, y, vx, vy)
+	w.camera.Update(1 / float64(ebiten.TPS()))
+	w.minimap.Snap(w.camera.X, w.camera.Y)
+
+	g.preloadChunks()
+}
+
+// preloadChunks подгружает чанки вокруг камеры: радиус растёт, когда
+// камера отдалена или миникарта охватывает больше, чем основной экран
+func (g *Game) preloadChunks() {
+	w := g.world
+	chunk := float64(tilemap.ChunkSize * w.m.TileWidth)
+	radius := tilemap.DefaultRadius
+	for _, c := range []*camera.Camera{w.camera, w.minimap} {
+		zoom := math.Min(c.Zoom, c.ZoomTarget())
+		half := math.Max(float64(c.Viewport.Dx()), float64(c.Viewport.Dy())) / zoom / 2
+		if r := int(math.Ceil(half / chunk)); r > radius {
+			radius = r
+		}
+	}
+	w.renderer.Radius = radius
+	w.renderer.Update(w.camera.X, w.camera.Y)
+}
+
+// drawWorld рисует карту через основную камеру: землю и декор, сущности
+// (системы фазы отрисовки), верхний слой, затем миникарту
+func (g *Game) drawWorld(screen *ebiten.Image) {
+	w := g.world
+	if w == nil {
+		return
+	}
+	w.renderer.ResetStats()
+
+	cam := w.camera
+	target := cam.Target(screen)
+	geoM, view := cam.GeoM(), cam.View()
+	w.renderer.Draw(target, geoM, view, tilemap.Ground, tilemap.Decoration)
+	*ecs.Resource[renderFrame](w.ecs) = renderFrame{screen: target, cam: cam, alpha: w.clock.Alpha()}
+	w.systems.Render(w.ecs)
+	w.renderer.Draw(target, geoM, view, tilemap.Overlay)
+
+	g.drawMinimap(screen)
+}
+
+// drawMinimap рисует уменьшенную карту в рамке и отмечает на ней героя
+// и остальные сущности
+func (g *Game) drawMinimap(screen *ebiten.Image) {
+	mm := g.world.minimap
+	r := mm.Viewport
+	border := float64(g.uiTheme.Px(2))
+	ebitenutil.DrawRect(screen, float64(r.Min.X)-border, float64(r.Min.Y)-border,
+		float64(r.Dx())+2*border, float64(r.Dy())+2*border, color.RGBA{230, 220, 200, 200})
+	ebitenutil.DrawRect(screen, float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy()), color.RGBA{0, 0, 0, 255})
+
+	target := mm.Target(screen)
+	g.world.renderer.Draw(target, mm.GeoM(), mm.View(), tilemap.Ground, tilemap.Decoration, tilemap.Overlay)
+
+	c := g.world.c
+	size := float64(g.uiTheme.Px(4))
+	ecs.Each2(c.Figure, c.Position, func(e ecs.Entity, _ *entities.Figure, pos *entities.Position) {
+		clr := color.RGBA{200, 200, 200, 255}
+		if c.Hero.Has(e) {
+			clr = color.RGBA{255, 230, 120, 255}
+		}
+		x, y := mm.WorldToScreen(pos.X, pos.Y)
+		ebitenutil.DrawRect(target, x-size/2, y-size/2, size, size, clr)
+	})
+}
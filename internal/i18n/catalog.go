@@ -0,0 +1,227 @@
+// Package i18n загружает таблицы строк из файлов assets/locales/<язык>.json,
+// переводит ключи с цепочкой запасных языков, подставляет {имя}-параметры и
+// выбирает форму множественного числа по правилам CLDR.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Message — строка перевода: либо одна форма, либо формы по категориям
+// множественного числа ({"one": "...", "few": "...", "many": "..."})
+type Message struct {
+	Text   string
+	Plural map[PluralCategory]string
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		m.Text = text
+		return nil
+	}
+
+	var forms map[PluralCategory]string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return fmt.Errorf("message must be a string or an object of plural forms")
+	}
+	m.Plural = forms
+	m.Text = forms[PluralOther]
+	return nil
+}
+
+// Catalog — таблица строк одного языка
+type Catalog struct {
+	// Locale — код языка, совпадает с именем файла (ru, en, pt-BR)
+	Locale string `json:"locale"`
+	// Fallback — языки, к которым обращаться за отсутствующими строками
+	Fallback []string `json:"fallback"`
+
+	// Name — название языка на нём самом ("Русский"), для выбора языка
+	Name string `json:"name"`
+	// Script — письменность по ISO 15924 (Latn, Cyrl, Hans), чтобы подобрать шрифт
+	Script string `json:"script"`
+	// Fonts — семейства шрифтов, без которых текст языка не отрисовать
+	Fonts []string `json:"fonts,omitempty"`
+
+	Messages map[string]Message `json:"messages"`
+}
+
+// Bundle — все загруженные каталоги
+type Bundle struct {
+	// DefaultLocale — последний язык любой цепочки запасных языков
+	DefaultLocale string
+
+	catalogs map[string]*Catalog
+}
+
+func NewBundle(defaultLocale string) *Bundle {
+	return &Bundle{
+		DefaultLocale: defaultLocale,
+		catalogs:      make(map[string]*Catalog),
+	}
+}
+
+// LoadDir загружает все файлы *.json из каталога dir файловой системы fsys
+func (b *Bundle) LoadDir(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("i18n: read %s: %w", dir, err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".json" {
+			continue
+		}
+		if err := b.LoadFile(fsys, path.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	if len(b.catalogs) == 0 {
+		return fmt.Errorf("i18n: no locale files in %s", dir)
+	}
+	return nil
+}
+
+// LoadFile загружает один каталог; код языка берётся из имени файла,
+// если он не указан в самом файле
+func (b *Bundle) LoadFile(fsys fs.FS, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("i18n: read %s: %w", name, err)
+	}
+
+	c := &Catalog{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("i18n: parse %s: %w", name, err)
+	}
+	if c.Locale == "" {
+		c.Locale = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+	if c.Name == "" {
+		c.Name = c.Locale
+	}
+	if c.Messages == nil {
+		c.Messages = map[string]Message{}
+	}
+
+	b.catalogs[c.Locale] = c
+	return nil
+}
+
+// Locales возвращает коды загруженных языков в алфавитном порядке
+func (b *Bundle) Locales() []string {
+	locales := make([]string, 0, len(b.catalogs))
+	for l := range b.catalogs {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Language — описание доступного языка для экрана настроек
+type Language struct {
+	Code   string
+	Name   string
+	Script string
+	Fonts  []string
+}
+
+// Languages возвращает загруженные языки в порядке кодов
+func (b *Bundle) Languages() []Language {
+	var out []Language
+	for _, code := range b.Locales() {
+		c := b.catalogs[code]
+		out = append(out, Language{
+			Code:   c.Locale,
+			Name:   c.Name,
+			Script: c.Script,
+			Fonts:  c.Fonts,
+		})
+	}
+	return out
+}
+
+// Catalog возвращает каталог языка или nil
+func (b *Bundle) Catalog(locale string) *Catalog {
+	return b.catalogs[locale]
+}
+
+// chain строит цепочку поиска строки: сам язык, его базовый язык
+// (pt-BR → pt), явные запасные языки каталога и язык по умолчанию
+func (b *Bundle) chain(locale string) []*Catalog {
+	var out []*Catalog
+	seen := map[string]bool{}
+
+	var add func(l string)
+	add = func(l string) {
+		if l == "" || seen[l] {
+			return
+		}
+		seen[l] = true
+
+		c, ok := b.catalogs[l]
+		if ok {
+			out = append(out, c)
+		}
+		if base := baseLanguage(l); base != strings.ToLower(l) {
+			add(base)
+		}
+		if ok {
+			for _, f := range c.Fallback {
+				add(f)
+			}
+		}
+	}
+
+	add(locale)
+	add(b.DefaultLocale)
+	return out
+}
+
+// MissingKeys сравнивает каталоги с объединением всех ключей и возвращает
+// для каждого языка отсортированный список ключей, которых в нём нет.
+// Для строк с формами множественного числа недостающая форма, нужная
+// языку, записывается как "ключ[few]". Языки без пропусков в результат не попадают.
+func (b *Bundle) MissingKeys() map[string][]string {
+	all := map[string]bool{}
+	for _, c := range b.catalogs {
+		for k := range c.Messages {
+			all[k] = true
+		}
+	}
+
+	missing := map[string][]string{}
+	for locale, c := range b.catalogs {
+		required := requiredPluralForms(locale)
+		for k := range all {
+			m, ok := c.Messages[k]
+			if !ok {
+				missing[locale] = append(missing[locale], k)
+				continue
+			}
+			if m.Plural == nil {
+				continue
+			}
+			for _, cat := range required {
+				if _, ok := m.Plural[cat]; !ok {
+					missing[locale] = append(missing[locale], k+"["+string(cat)+"]")
+				}
+			}
+		}
+		sort.Strings(missing[locale])
+	}
+
+	for locale, keys := range missing {
+		if len(keys) == 0 {
+			delete(missing, locale)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,131 @@
+package i18n
+
+import "strings"
+
+// PluralCategory — категория множественного числа по CLDR
+type PluralCategory string
+
+const (
+	PluralZero  PluralCategory = "zero"
+	PluralOne   PluralCategory = "one"
+	PluralTwo   PluralCategory = "two"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// pluralRule выбирает категорию для целого неотрицательного числа
+type pluralRule func(n int) PluralCategory
+
+// pluralRules — правила CLDR для целых чисел по базовому языку
+var pluralRules = map[string]pluralRule{
+	"en": ruleOneOther,
+	"de": ruleOneOther,
+	"es": ruleOneOther,
+	"it": ruleOneOther,
+	"nl": ruleOneOther,
+	"fr": ruleFrench,
+	"pt": ruleFrench,
+	"ru": ruleEastSlavic,
+	"uk": ruleEastSlavic,
+	"be": ruleEastSlavic,
+	"pl": rulePolish,
+	"cs": ruleCzech,
+	"ja": ruleOther,
+	"ko": ruleOther,
+	"zh": ruleOther,
+}
+
+// PluralFor возвращает категорию числа n для языка lang
+func PluralFor(lang string, n int) PluralCategory {
+	if n < 0 {
+		n = -n
+	}
+	rule, ok := pluralRules[baseLanguage(lang)]
+	if !ok {
+		rule = ruleOneOther
+	}
+	return rule(n)
+}
+
+func baseLanguage(tag string) string {
+	tag = strings.ToLower(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+func ruleOther(n int) PluralCategory {
+	return PluralOther
+}
+
+func ruleOneOther(n int) PluralCategory {
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// ruleFrench: 0 и 1 — one
+func ruleFrench(n int) PluralCategory {
+	if n == 0 || n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// ruleEastSlavic: 1, 21, 31… — one; 2–4, 22–24… — few; остальные — many
+func ruleEastSlavic(n int) PluralCategory {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return PluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+// rulePolish: 1 — one; 2–4, 22–24… — few; остальные — many
+func rulePolish(n int) PluralCategory {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case n == 1:
+		return PluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+// ruleCzech: 1 — one; 2–4 — few; остальные — other
+func ruleCzech(n int) PluralCategory {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n >= 2 && n <= 4:
+		return PluralFew
+	default:
+		return PluralOther
+	}
+}
+
+// requiredPluralForms возвращает категории, которые правило языка выдаёт
+// для целых чисел, в порядке CLDR
+func requiredPluralForms(lang string) []PluralCategory {
+	seen := map[PluralCategory]bool{}
+	for n := 0; n < 200; n++ {
+		seen[PluralFor(lang, n)] = true
+	}
+
+	var out []PluralCategory
+	for _, c := range []PluralCategory{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther} {
+		if seen[c] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
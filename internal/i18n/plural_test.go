@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPluralFor(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    int
+		want PluralCategory
+	}{
+		{"ru", 0, PluralMany},
+		{"ru", 1, PluralOne},
+		{"ru", 2, PluralFew},
+		{"ru", 4, PluralFew},
+		{"ru", 5, PluralMany},
+		{"ru", 11, PluralMany},
+		{"ru", 12, PluralMany},
+		{"ru", 14, PluralMany},
+		{"ru", 21, PluralOne},
+		{"ru", 22, PluralFew},
+		{"ru", 25, PluralMany},
+		{"ru", 101, PluralOne},
+		{"ru", 111, PluralMany},
+		{"ru", 112, PluralMany},
+		{"ru", 122, PluralFew},
+		{"ru", -21, PluralOne},
+		{"ru-RU", 3, PluralFew},
+		{"uk_UA", 5, PluralMany},
+
+		{"en", 0, PluralOther},
+		{"en", 1, PluralOne},
+		{"en", 2, PluralOther},
+		{"en", 21, PluralOther},
+		{"fr", 0, PluralOne},
+		{"fr", 2, PluralOther},
+
+		{"pl", 1, PluralOne},
+		{"pl", 21, PluralMany},
+		{"pl", 22, PluralFew},
+		{"pl", 12, PluralMany},
+		{"cs", 3, PluralFew},
+		{"cs", 5, PluralOther},
+		{"ja", 1, PluralOther},
+
+		// Неизвестный язык считается как английский
+		{"xx", 1, PluralOne},
+		{"xx", 2, PluralOther},
+	}
+	for _, tt := range tests {
+		if got := PluralFor(tt.lang, tt.n); got != tt.want {
+			t.Errorf("PluralFor(%q, %d) = %s, want %s", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestTranslatorPlural(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/ru.json": {Data: []byte(`{
+			"locale": "ru",
+			"fallback": ["en"],
+			"messages": {
+				"Apples": {"one": "{count} яблоко", "few": "{count} яблока", "many": "{count} яблок"}
+			}
+		}`)},
+		"locales/en.json": {Data: []byte(`{
+			"locale": "en",
+			"messages": {
+				"Apples": {"one": "{count} apple", "other": "{count} apples"},
+				"Pears": {"one": "{count} pear in {where}", "other": "{count} pears in {where}"}
+			}
+		}`)},
+	}
+	b := NewBundle("en")
+	if err := b.LoadDir(fsys, "locales"); err != nil {
+		t.Fatal(err)
+	}
+
+	ru := b.Translator("ru")
+	for n, want := range map[int]string{1: "1 яблоко", 3: "3 яблока", 11: "11 яблок", 21: "21 яблоко", 25: "25 яблок"} {
+		if got := ru.Plural("Apples", n, nil); got != want {
+			t.Errorf("ru Apples(%d) = %q, want %q", n, got, want)
+		}
+	}
+	// Строка из запасного языка выбирает форму по русскому правилу; формы
+	// many у английской строки нет, и берётся other
+	if got, want := ru.Plural("Pears", 5, Vars{"where": "box"}), "5 pears in box"; got != want {
+		t.Errorf("ru Pears(5) = %q, want %q", got, want)
+	}
+	if got, want := b.Translator("en").Plural("Apples", 1, nil), "1 apple"; got != want {
+		t.Errorf("en Apples(1) = %q, want %q", got, want)
+	}
+	if got := ru.Plural("Plums", 2, nil); got != "Plums" {
+		t.Errorf("missing key = %q, want the key", got)
+	}
+}
+
+// TestShippedLocales — каталоги игры загружаются, и ни в одном не
+// пропущены строки и формы множественного числа
+func TestShippedLocales(t *testing.T) {
+	b := NewBundle("en")
+	if err := b.LoadDir(os.DirFS("../../assets"), "locales"); err != nil {
+		t.Fatal(err)
+	}
+	for locale, keys := range b.MissingKeys() {
+		t.Errorf("%s is missing %v", locale, keys)
+	}
+}
@@ -0,0 +1,110 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Vars — значения {имя}-параметров строки
+type Vars map[string]interface{}
+
+// Translator переводит ключи для одного языка
+type Translator struct {
+	locale string
+	chain  []*Catalog
+}
+
+// Translator возвращает переводчик для языка с цепочкой запасных языков
+func (b *Bundle) Translator(locale string) *Translator {
+	return &Translator{
+		locale: locale,
+		chain:  b.chain(locale),
+	}
+}
+
+// Locale возвращает код языка переводчика
+func (t *Translator) Locale() string {
+	return t.locale
+}
+
+func (t *Translator) lookup(key string) (Message, bool) {
+	for _, c := range t.chain {
+		if m, ok := c.Messages[key]; ok {
+			return m, true
+		}
+	}
+	return Message{}, false
+}
+
+// T возвращает перевод ключа; ненайденный ключ возвращается как есть
+func (t *Translator) T(key string) string {
+	m, ok := t.lookup(key)
+	if !ok {
+		return key
+	}
+	return m.Text
+}
+
+// Format переводит ключ и подставляет {имя}-параметры
+func (t *Translator) Format(key string, vars Vars) string {
+	return interpolate(t.T(key), vars)
+}
+
+// Plural выбирает форму множественного числа для n по правилам языка
+// переводчика и подставляет параметры; {count} подставляется автоматически
+func (t *Translator) Plural(key string, n int, vars Vars) string {
+	m, ok := t.lookup(key)
+	if !ok {
+		return key
+	}
+
+	s := m.Text
+	if m.Plural != nil {
+		// Недостающая форма заменяется первой имеющейся из other, many, one
+		for _, cat := range []PluralCategory{PluralFor(t.locale, n), PluralOther, PluralMany, PluralOne} {
+			if form, ok := m.Plural[cat]; ok {
+				s = form
+				break
+			}
+		}
+	}
+
+	all := Vars{"count": n}
+	for k, v := range vars {
+		all[k] = v
+	}
+	return interpolate(s, all)
+}
+
+// interpolate заменяет {имя} значениями из vars; неизвестные имена
+// остаются без изменений
+func interpolate(s string, vars Vars) string {
+	if len(vars) == 0 || !strings.Contains(s, "{") {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			b.WriteString(s)
+			break
+		}
+		end := strings.IndexByte(s[open:], '}')
+		if end < 0 {
+			b.WriteString(s)
+			break
+		}
+		end += open
+
+		b.WriteString(s[:open])
+		name := s[open+1 : end]
+		if v, ok := vars[name]; ok {
+			fmt.Fprint(&b, v)
+		} else {
+			b.WriteString(s[open : end+1])
+		}
+		s = s[end+1:]
+	}
+	return b.String()
+}
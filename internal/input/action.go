@@ -0,0 +1,63 @@
+// Package input связывает именованные действия с клавишами, кнопками мыши и
+// геймпада и сообщает их состояние за кадр: нажато, только что нажато,
+// только что отпущено и автоповтор при удержании.
+package input
+
+// Action — именованное игровое действие
+type Action string
+
+const (
+	MenuUp    Action = "menu_up"
+	MenuDown  Action = "menu_down"
+	MenuLeft  Action = "menu_left"
+	MenuRight Action = "menu_right"
+	Confirm   Action = "confirm"
+	Back      Action = "back"
+
+	// ValueDecrease и ValueIncrease крупным шагом меняют значение ползунка
+	ValueDecrease Action = "value_decrease"
+	ValueIncrease Action = "value_increase"
+
+	// PointerPress — основная кнопка указателя (клик по виджетам)
+	PointerPress Action = "pointer_press"
+
+	// DebugOverlay показывает и скрывает отладочную информацию
+	DebugOverlay Action = "debug_overlay"
+
+	// QuickSave и QuickLoad — быстрое сохранение и загрузка во время игры;
+	// SaveMenu открывает список слотов сохранения
+	QuickSave Action = "quick_save"
+	QuickLoad Action = "quick_load"
+	SaveMenu  Action = "save_menu"
+
+	// ZoomIn и ZoomOut приближают и отдаляют камеру мира
+	ZoomIn  Action = "zoom_in"
+	ZoomOut Action = "zoom_out"
+
+	// Attack — удар героя в мире
+	Attack Action = "attack"
+
+	// Pause ставит игру на паузу и открывает настройки поверх неё
+	Pause Action = "pause"
+)
+
+// Actions — все известные действия в порядке отображения
+var Actions = []Action{
+	MenuUp,
+	MenuDown,
+	MenuLeft,
+	MenuRight,
+	Confirm,
+	Back,
+	ValueDecrease,
+	ValueIncrease,
+	PointerPress,
+	DebugOverlay,
+	QuickSave,
+	QuickLoad,
+	SaveMenu,
+	ZoomIn,
+	ZoomOut,
+	Attack,
+	Pause,
+}
@@ -0,0 +1,264 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// DeviceKind — тип устройства, к которому относится привязка
+type DeviceKind int
+
+const (
+	DeviceKeyboard DeviceKind = iota
+	DeviceMouse
+	DeviceGamepad
+)
+
+var devicePrefixes = map[DeviceKind]string{
+	DeviceKeyboard: "key",
+	DeviceMouse:    "mouse",
+	DeviceGamepad:  "pad",
+}
+
+var mouseButtonNames = map[string]ebiten.MouseButton{
+	"left":   ebiten.MouseButtonLeft,
+	"right":  ebiten.MouseButtonRight,
+	"middle": ebiten.MouseButtonMiddle,
+}
+
+// padButtonNames — кнопки стандартной раскладки геймпада (Xbox-обозначения)
+var padButtonNames = map[string]ebiten.StandardGamepadButton{
+	"A":         ebiten.StandardGamepadButtonRightBottom,
+	"B":         ebiten.StandardGamepadButtonRightRight,
+	"X":         ebiten.StandardGamepadButtonRightLeft,
+	"Y":         ebiten.StandardGamepadButtonRightTop,
+	"LB":        ebiten.StandardGamepadButtonFrontTopLeft,
+	"RB":        ebiten.StandardGamepadButtonFrontTopRight,
+	"LT":        ebiten.StandardGamepadButtonFrontBottomLeft,
+	"RT":        ebiten.StandardGamepadButtonFrontBottomRight,
+	"Back":      ebiten.StandardGamepadButtonCenterLeft,
+	"Start":     ebiten.StandardGamepadButtonCenterRight,
+	"Home":      ebiten.StandardGamepadButtonCenterCenter,
+	"LS":        ebiten.StandardGamepadButtonLeftStick,
+	"RS":        ebiten.StandardGamepadButtonRightStick,
+	"DPadUp":    ebiten.StandardGamepadButtonLeftTop,
+	"DPadDown":  ebiten.StandardGamepadButtonLeftBottom,
+	"DPadLeft":  ebiten.StandardGamepadButtonLeftLeft,
+	"DPadRight": ebiten.StandardGamepadButtonLeftRight,
+}
+
+// Binding — одна физическая кнопка, назначенная действию.
+// В текстовом виде записывается как "key:Enter", "mouse:left" или "pad:A".
+type Binding struct {
+	Kind DeviceKind
+	Key  ebiten.Key
+	// Mouse и Pad используются для соответствующих типов устройств;
+	// Stick, если задан, заменяет кнопку геймпада направлением стика
+	Mouse ebiten.MouseButton
+	Pad   ebiten.StandardGamepadButton
+	Stick StickDirection
+}
+
+func KeyBinding(k ebiten.Key) Binding {
+	return Binding{Kind: DeviceKeyboard, Key: k}
+}
+
+func MouseBinding(b ebiten.MouseButton) Binding {
+	return Binding{Kind: DeviceMouse, Mouse: b}
+}
+
+func PadBinding(b ebiten.StandardGamepadButton) Binding {
+	return Binding{Kind: DeviceGamepad, Pad: b}
+}
+
+func StickBinding(d StickDirection) Binding {
+	return Binding{Kind: DeviceGamepad, Stick: d}
+}
+
+// String возвращает текстовое представление привязки
+func (b Binding) String() string {
+	var name string
+	switch b.Kind {
+	case DeviceKeyboard:
+		name = b.Key.String()
+	case DeviceMouse:
+		for n, mb := range mouseButtonNames {
+			if mb == b.Mouse {
+				name = n
+			}
+		}
+	case DeviceGamepad:
+		if b.Stick != StickNone {
+			for n, d := range stickNames {
+				if d == b.Stick {
+					name = n
+				}
+			}
+			break
+		}
+		for n, pb := range padButtonNames {
+			if pb == b.Pad {
+				name = n
+			}
+		}
+	}
+	return devicePrefixes[b.Kind] + ":" + name
+}
+
+// ParseBinding разбирает текстовое представление привязки
+func ParseBinding(s string) (Binding, error) {
+	prefix, name, ok := strings.Cut(s, ":")
+	if !ok {
+		return Binding{}, fmt.Errorf("input: binding %q has no device prefix", s)
+	}
+
+	switch prefix {
+	case devicePrefixes[DeviceKeyboard]:
+		var k ebiten.Key
+		if err := k.UnmarshalText([]byte(name)); err != nil {
+			return Binding{}, fmt.Errorf("input: binding %q: %w", s, err)
+		}
+		return KeyBinding(k), nil
+	case devicePrefixes[DeviceMouse]:
+		mb, ok := mouseButtonNames[name]
+		if !ok {
+			return Binding{}, fmt.Errorf("input: unknown mouse button in %q", s)
+		}
+		return MouseBinding(mb), nil
+	case devicePrefixes[DeviceGamepad]:
+		if d, ok := stickNames[name]; ok {
+			return StickBinding(d), nil
+		}
+		pb, ok := padButtonNames[name]
+		if !ok {
+			return Binding{}, fmt.Errorf("input: unknown gamepad button in %q", s)
+		}
+		return PadBinding(pb), nil
+	}
+	return Binding{}, fmt.Errorf("input: unknown device %q in %q", prefix, s)
+}
+
+func (b Binding) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+func (b *Binding) UnmarshalText(text []byte) error {
+	parsed, err := ParseBinding(string(text))
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+// Bindings — таблица привязок действий; сериализуется в JSON
+type Bindings map[Action][]Binding
+
+// DefaultBindings возвращает стандартную раскладку управления
+func DefaultBindings() Bindings {
+	return Bindings{
+		MenuUp: {
+			KeyBinding(ebiten.KeyArrowUp), KeyBinding(ebiten.KeyW),
+			PadBinding(ebiten.StandardGamepadButtonLeftTop),
+			StickBinding(LeftStickUp),
+		},
+		MenuDown: {
+			KeyBinding(ebiten.KeyArrowDown), KeyBinding(ebiten.KeyS),
+			PadBinding(ebiten.StandardGamepadButtonLeftBottom),
+			StickBinding(LeftStickDown),
+		},
+		MenuLeft: {
+			KeyBinding(ebiten.KeyArrowLeft), KeyBinding(ebiten.KeyA),
+			PadBinding(ebiten.StandardGamepadButtonLeftLeft),
+			StickBinding(LeftStickLeft),
+		},
+		MenuRight: {
+			KeyBinding(ebiten.KeyArrowRight), KeyBinding(ebiten.KeyD),
+			PadBinding(ebiten.StandardGamepadButtonLeftRight),
+			StickBinding(LeftStickRight),
+		},
+		Confirm: {
+			KeyBinding(ebiten.KeyEnter), KeyBinding(ebiten.KeySpace),
+			PadBinding(ebiten.StandardGamepadButtonRightBottom),
+		},
+		Back: {
+			KeyBinding(ebiten.KeyEscape),
+			PadBinding(ebiten.StandardGamepadButtonRightRight),
+		},
+		ValueDecrease: {
+			KeyBinding(ebiten.KeyPageDown),
+			PadBinding(ebiten.StandardGamepadButtonFrontTopLeft),
+		},
+		ValueIncrease: {
+			KeyBinding(ebiten.KeyPageUp),
+			PadBinding(ebiten.StandardGamepadButtonFrontTopRight),
+		},
+		PointerPress: {
+			MouseBinding(ebiten.MouseButtonLeft),
+		},
+		DebugOverlay: {
+			KeyBinding(ebiten.KeyF3),
+		},
+		QuickSave: {
+			KeyBinding(ebiten.KeyF5),
+		},
+		QuickLoad: {
+			KeyBinding(ebiten.KeyF9),
+		},
+		SaveMenu: {
+			KeyBinding(ebiten.KeyF6),
+			PadBinding(ebiten.StandardGamepadButtonCenterRight),
+		},
+		ZoomIn: {
+			KeyBinding(ebiten.KeyEqual), KeyBinding(ebiten.KeyNumpadAdd),
+			PadBinding(ebiten.StandardGamepadButtonFrontBottomRight),
+		},
+		ZoomOut: {
+			KeyBinding(ebiten.KeyMinus), KeyBinding(ebiten.KeyNumpadSubtract),
+			PadBinding(ebiten.StandardGamepadButtonFrontBottomLeft),
+		},
+		Attack: {
+			KeyBinding(ebiten.KeyF), KeyBinding(ebiten.KeyJ),
+			PadBinding(ebiten.StandardGamepadButtonRightLeft),
+		},
+		Pause: {
+			KeyBinding(ebiten.KeyP),
+			PadBinding(ebiten.StandardGamepadButtonCenterLeft),
+		},
+	}
+}
+
+// Clone возвращает независимую копию таблицы
+func (bs Bindings) Clone() Bindings {
+	out := make(Bindings, len(bs))
+	for a, list := range bs {
+		out[a] = append([]Binding(nil), list...)
+	}
+	return out
+}
+
+// LoadBindings читает таблицу привязок из JSON; действия, отсутствующие
+// в файле, получают привязки по умолчанию
+func LoadBindings(r io.Reader) (Bindings, error) {
+	loaded := Bindings{}
+	if err := json.NewDecoder(r).Decode(&loaded); err != nil {
+		return nil, fmt.Errorf("input: decode bindings: %w", err)
+	}
+
+	bs := DefaultBindings()
+	for a, list := range loaded {
+		bs[a] = list
+	}
+	return bs, nil
+}
+
+// Save записывает таблицу привязок в JSON
+func (bs Bindings) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bs)
+}
@@ -0,0 +1,162 @@
+package input
+
+import (
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// DefaultDeadZone — радиус мёртвой зоны стика, в долях полного отклонения
+const DefaultDeadZone = 0.35
+
+// StickDirection — направление стика, используемое как цифровая кнопка
+type StickDirection int
+
+const (
+	StickNone StickDirection = iota
+	LeftStickUp
+	LeftStickDown
+	LeftStickLeft
+	LeftStickRight
+	RightStickUp
+	RightStickDown
+	RightStickLeft
+	RightStickRight
+)
+
+var stickNames = map[string]StickDirection{
+	"LeftStickUp":     LeftStickUp,
+	"LeftStickDown":   LeftStickDown,
+	"LeftStickLeft":   LeftStickLeft,
+	"LeftStickRight":  LeftStickRight,
+	"RightStickUp":    RightStickUp,
+	"RightStickDown":  RightStickDown,
+	"RightStickLeft":  RightStickLeft,
+	"RightStickRight": RightStickRight,
+}
+
+// axes возвращает оси стика и знак направления вдоль основной оси
+func (d StickDirection) axes() (h, v ebiten.StandardGamepadAxis, horizontal bool, sign float64) {
+	h, v = ebiten.StandardGamepadAxisLeftStickHorizontal, ebiten.StandardGamepadAxisLeftStickVertical
+	if d >= RightStickUp {
+		h, v = ebiten.StandardGamepadAxisRightStickHorizontal, ebiten.StandardGamepadAxisRightStickVertical
+	}
+
+	switch d {
+	case LeftStickUp, RightStickUp:
+		return h, v, false, -1
+	case LeftStickDown, RightStickDown:
+		return h, v, false, 1
+	case LeftStickLeft, RightStickLeft:
+		return h, v, true, -1
+	default:
+		return h, v, true, 1
+	}
+}
+
+// stickDown сообщает, отклонён ли стик в направлении d за пределы мёртвой зоны.
+// Мёртвая зона радиальная; направление засчитывается в секторе ±60° от оси,
+// чтобы диагональ нажимала обе соседние стрелки.
+func stickDown(id ebiten.GamepadID, d StickDirection, deadZone float64) bool {
+	h, v, horizontal, sign := d.axes()
+	x := ebiten.StandardGamepadAxisValue(id, h)
+	y := ebiten.StandardGamepadAxisValue(id, v)
+
+	magnitude := math.Hypot(x, y)
+	if magnitude < deadZone {
+		return false
+	}
+
+	component := y
+	if horizontal {
+		component = x
+	}
+	return component*sign >= magnitude*0.5
+}
+
+// stickActive сообщает, отклонён ли любой стик геймпада за мёртвую зону
+func stickActive(id ebiten.GamepadID, deadZone float64) bool {
+	for d := LeftStickUp; d <= RightStickRight; d++ {
+		if stickDown(id, d, deadZone) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateGamepads отслеживает подключение и отключение геймпадов
+func (m *Manager) updateGamepads() {
+	m.connected = inpututil.AppendJustConnectedGamepadIDs(m.connected[:0])
+	for _, id := range m.connected {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			log.Printf("Gamepad connected: %s (id %d)", ebiten.GamepadName(id), id)
+		} else {
+			log.Printf("Gamepad connected without standard layout, ignoring: %s (id %d)", ebiten.GamepadName(id), id)
+		}
+	}
+
+	for _, id := range m.gamepads {
+		if inpututil.IsGamepadJustDisconnected(id) {
+			log.Printf("Gamepad disconnected: id %d", id)
+		}
+	}
+
+	m.gamepads = ebiten.AppendGamepadIDs(m.gamepads[:0])
+
+	// Последний геймпад отключён — подсказки возвращаются к клавиатуре
+	if m.lastDevice == DeviceGamepad && !m.HasGamepad() {
+		m.lastDevice = DeviceKeyboard
+	}
+}
+
+// HasGamepad сообщает, подключён ли хотя бы один геймпад со стандартной раскладкой
+func (m *Manager) HasGamepad() bool {
+	for _, id := range m.gamepads {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateLastDevice запоминает, каким устройством игрок пользовался последним
+func (m *Manager) updateLastDevice() {
+	m.keysBuf = inpututil.AppendPressedKeys(m.keysBuf[:0])
+	if len(m.keysBuf) > 0 {
+		m.lastDevice = DeviceKeyboard
+	}
+
+	x, y := ebiten.CursorPosition()
+	if x != m.cursorX || y != m.cursorY {
+		m.cursorX, m.cursorY = x, y
+		m.lastDevice = DeviceMouse
+	}
+	for _, mb := range mouseButtonNames {
+		if ebiten.IsMouseButtonPressed(mb) {
+			m.lastDevice = DeviceMouse
+		}
+	}
+
+	for _, id := range m.gamepads {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for _, pb := range padButtonNames {
+			if ebiten.IsStandardGamepadButtonPressed(id, pb) {
+				m.lastDevice = DeviceGamepad
+				return
+			}
+		}
+		if stickActive(id, m.DeadZone) {
+			m.lastDevice = DeviceGamepad
+			return
+		}
+	}
+}
+
+// LastDevice возвращает устройство, которым игрок пользовался последним
+func (m *Manager) LastDevice() DeviceKind {
+	return m.lastDevice
+}
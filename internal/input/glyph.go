@@ -0,0 +1,58 @@
+package input
+
+// Glyph — подпись кнопки для экранной подсказки
+type Glyph struct {
+	// Gamepad — подпись относится к кнопке геймпада, а не клавиатуры
+	Gamepad bool
+	Label   string
+}
+
+// keyGlyphNames — короткие подписи клавиш, имя которых в ebiten слишком длинное
+var keyGlyphNames = map[string]string{
+	"Escape":     "Esc",
+	"ArrowUp":    "Up",
+	"ArrowDown":  "Down",
+	"ArrowLeft":  "Left",
+	"ArrowRight": "Right",
+	"PageUp":     "PgUp",
+	"PageDown":   "PgDn",
+}
+
+// padGlyphNames — короткие подписи направлений геймпада
+var padGlyphNames = map[string]string{
+	"DPadUp":    "D-Up",
+	"DPadDown":  "D-Down",
+	"DPadLeft":  "D-Left",
+	"DPadRight": "D-Right",
+}
+
+// Glyph возвращает подпись первой привязки действия для устройства, которым
+// игрок пользовался последним. Мышь показывается клавиатурными подписями.
+func (m *Manager) Glyph(a Action) (Glyph, bool) {
+	wantPad := m.lastDevice == DeviceGamepad
+
+	for _, b := range m.bindings[a] {
+		switch {
+		case wantPad && b.Kind == DeviceGamepad && b.Stick == StickNone:
+			_, name := splitBinding(b)
+			if short, ok := padGlyphNames[name]; ok {
+				name = short
+			}
+			return Glyph{Gamepad: true, Label: name}, true
+		case !wantPad && b.Kind == DeviceKeyboard:
+			name := b.Key.String()
+			if short, ok := keyGlyphNames[name]; ok {
+				name = short
+			}
+			return Glyph{Label: name}, true
+		}
+	}
+	return Glyph{}, false
+}
+
+// splitBinding возвращает префикс устройства и имя кнопки привязки
+func splitBinding(b Binding) (string, string) {
+	s := b.String()
+	prefix := devicePrefixes[b.Kind]
+	return prefix, s[len(prefix)+1:]
+}
@@ -0,0 +1,275 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Тайминги автоповтора по умолчанию, в тиках (60 TPS)
+const (
+	DefaultRepeatDelay    = 24
+	DefaultRepeatInterval = 6
+)
+
+// actionState — состояние действия за текущий тик
+type actionState struct {
+	held         bool
+	justPressed  bool
+	justReleased bool
+	repeated     bool
+	duration     int
+}
+
+// update продвигает состояние на один тик
+func (s *actionState) update(down bool, delay, interval int) {
+	s.justPressed = down && !s.held
+	s.justReleased = !down && s.held
+	s.held = down
+
+	if !down {
+		s.duration = 0
+		s.repeated = false
+		return
+	}
+
+	s.duration++
+	s.repeated = s.justPressed ||
+		(s.duration > delay && interval > 0 && (s.duration-delay)%interval == 0)
+}
+
+// Manager опрашивает устройства раз в тик и хранит состояние каждого действия
+type Manager struct {
+	bindings Bindings
+	states   map[Action]*actionState
+
+	// RepeatDelay — задержка до первого автоповтора, RepeatInterval — период повтора
+	RepeatDelay    int
+	RepeatInterval int
+
+	// DeadZone — радиальная мёртвая зона стиков
+	DeadZone float64
+
+	gamepads  []ebiten.GamepadID
+	connected []ebiten.GamepadID
+
+	lastDevice       DeviceKind
+	cursorX, cursorY int
+	keysBuf          []ebiten.Key
+
+	capturing   Action
+	captureKind DeviceKind
+	reserved    []Action
+	onCapture   func(Binding, bool)
+}
+
+func NewManager(bindings Bindings) *Manager {
+	if bindings == nil {
+		bindings = DefaultBindings()
+	}
+	return &Manager{
+		bindings:       bindings,
+		states:         make(map[Action]*actionState),
+		RepeatDelay:    DefaultRepeatDelay,
+		RepeatInterval: DefaultRepeatInterval,
+		DeadZone:       DefaultDeadZone,
+	}
+}
+
+// Update опрашивает клавиатуру, мышь и геймпады; вызывается один раз в начале тика
+func (m *Manager) Update() {
+	m.updateGamepads()
+	m.updateLastDevice()
+
+	// Пока идёт захват кнопки для переназначения, кнопки захватываемого
+	// устройства действий не вызывают; удержание при этом отслеживается,
+	// чтобы захваченная кнопка не сработала сразу после назначения. Другие
+	// устройства, в том числе мышь для интерфейса, работают как обычно.
+	suppress := m.capturing != ""
+	kind := m.captureKind
+	if suppress {
+		m.updateCapture()
+	}
+
+	for action, list := range m.bindings {
+		st := m.state(action)
+		down, free := false, false
+		for _, b := range list {
+			if m.bindingDown(b) {
+				down = true
+				if b.Kind != kind {
+					free = true
+				}
+			}
+		}
+		st.update(down, m.RepeatDelay, m.RepeatInterval)
+
+		if suppress && !free {
+			st.justPressed = false
+			st.justReleased = false
+			st.repeated = false
+		}
+	}
+}
+
+func (m *Manager) state(a Action) *actionState {
+	st, ok := m.states[a]
+	if !ok {
+		st = &actionState{}
+		m.states[a] = st
+	}
+	return st
+}
+
+func (m *Manager) bindingDown(b Binding) bool {
+	switch b.Kind {
+	case DeviceKeyboard:
+		return ebiten.IsKeyPressed(b.Key)
+	case DeviceMouse:
+		return ebiten.IsMouseButtonPressed(b.Mouse)
+	case DeviceGamepad:
+		for _, id := range m.gamepads {
+			if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+				continue
+			}
+			if b.Stick != StickNone {
+				if stickDown(id, b.Stick, m.DeadZone) {
+					return true
+				}
+			} else if ebiten.IsStandardGamepadButtonPressed(id, b.Pad) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Pressed сообщает, удерживается ли действие
+func (m *Manager) Pressed(a Action) bool {
+	return m.state(a).held
+}
+
+// JustPressed сообщает, было ли действие нажато в этом тике
+func (m *Manager) JustPressed(a Action) bool {
+	return m.state(a).justPressed
+}
+
+// JustReleased сообщает, было ли действие отпущено в этом тике
+func (m *Manager) JustReleased(a Action) bool {
+	return m.state(a).justReleased
+}
+
+// Repeated срабатывает в момент нажатия и затем периодически при удержании —
+// для навигации по меню
+func (m *Manager) Repeated(a Action) bool {
+	return m.state(a).repeated
+}
+
+// Duration возвращает число тиков, в течение которых действие удерживается
+func (m *Manager) Duration(a Action) int {
+	return m.state(a).duration
+}
+
+// Bindings возвращает копию текущей таблицы привязок
+func (m *Manager) Bindings() Bindings {
+	return m.bindings.Clone()
+}
+
+// SetBindings заменяет таблицу привязок
+func (m *Manager) SetBindings(bs Bindings) {
+	m.bindings = bs.Clone()
+}
+
+// Bind заменяет привязки действия
+func (m *Manager) Bind(a Action, list ...Binding) {
+	m.bindings[a] = append([]Binding(nil), list...)
+}
+
+// Rebind ожидает следующую кнопку устройства, которым игрок пользовался
+// последним, и назначает её действию вместо привязок того же устройства.
+// Мышь здесь считается клавиатурой: кнопки мыши не захватываются, чтобы
+// указатель продолжал работать с интерфейсом. Кнопка, уже назначенная
+// одному из действий reserved, не назначается и отменяет захват. done
+// вызывается с нажатой кнопкой и тем, назначена ли она.
+func (m *Manager) Rebind(a Action, reserved []Action, done func(b Binding, ok bool)) {
+	m.capturing = a
+	m.captureKind = DeviceKeyboard
+	if m.lastDevice == DeviceGamepad {
+		m.captureKind = DeviceGamepad
+	}
+	m.reserved = append(m.reserved[:0], reserved...)
+	m.onCapture = done
+}
+
+// Capturing сообщает, ожидается ли кнопка для переназначения
+func (m *Manager) Capturing() bool {
+	return m.capturing != ""
+}
+
+// CancelRebind прерывает ожидание кнопки
+func (m *Manager) CancelRebind() {
+	m.capturing = ""
+	m.onCapture = nil
+}
+
+func (m *Manager) updateCapture() {
+	b, ok := m.justPressedBinding(m.captureKind)
+	if !ok {
+		return
+	}
+
+	done := m.onCapture
+	a := m.capturing
+	m.CancelRebind()
+
+	if m.boundTo(b, m.reserved) {
+		if done != nil {
+			done(b, false)
+		}
+		return
+	}
+
+	kept := m.bindings[a][:0:0]
+	for _, old := range m.bindings[a] {
+		if old.Kind != b.Kind {
+			kept = append(kept, old)
+		}
+	}
+	m.bindings[a] = append([]Binding{b}, kept...)
+
+	if done != nil {
+		done(b, true)
+	}
+}
+
+// boundTo сообщает, назначена ли кнопка одному из действий
+func (m *Manager) boundTo(b Binding, actions []Action) bool {
+	for _, a := range actions {
+		for _, other := range m.bindings[a] {
+			if other == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// justPressedBinding ищет кнопку устройства kind, нажатую в этом тике
+func (m *Manager) justPressedBinding(kind DeviceKind) (Binding, bool) {
+	switch kind {
+	case DeviceKeyboard:
+		for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+			if inpututil.IsKeyJustPressed(k) {
+				return KeyBinding(k), true
+			}
+		}
+	case DeviceGamepad:
+		for _, id := range m.gamepads {
+			for _, pb := range padButtonNames {
+				if inpututil.IsStandardGamepadButtonJustPressed(id, pb) {
+					return PadBinding(pb), true
+				}
+			}
+		}
+	}
+	return Binding{}, false
+}
@@ -0,0 +1,215 @@
+// Package mixer сводит громкость всех звуковых плееров игры через шины.
+// Громкость плеера = его собственное усиление × шина × master, с учётом
+// выключения звука, уровня, заданного сценой, и приглушения (ducking) шины,
+// пока звучит другая шина — например, музыка тише, пока говорит персонаж.
+package mixer
+
+import "math"
+
+// Bus — имя шины
+type Bus string
+
+const (
+	Master   Bus = "master"
+	Music    Bus = "music"
+	SFX      Bus = "sfx"
+	Voice    Bus = "voice"
+	Ambience Bus = "ambience"
+)
+
+// Buses — все шины в порядке отображения
+var Buses = []Bus{Master, Music, SFX, Voice, Ambience}
+
+// Player — то, что микшеру нужно от плеера; *audio.Player из ebiten подходит
+type Player interface {
+	SetVolume(volume float64)
+	IsPlaying() bool
+}
+
+// DuckRule приглушает шину Target до уровня Level, пока на шине Trigger
+// что-нибудь играет
+type DuckRule struct {
+	Trigger Bus
+	Target  Bus
+	Level   float64
+}
+
+// DefaultDuckRules — речь приглушает музыку и окружение
+var DefaultDuckRules = []DuckRule{
+	{Trigger: Voice, Target: Music, Level: 0.35},
+	{Trigger: Voice, Target: Ambience, Level: 0.5},
+}
+
+// DuckSpeed — на сколько за тик меняется приглушение; 0.05 — около трети секунды
+// на полный переход при 60 TPS
+const DuckSpeed = 0.05
+
+type bus struct {
+	volume float64
+	muted  bool
+	level  float64 // уровень, заданный сценой
+	duck   float64 // текущее приглушение, плавно идёт к цели
+}
+
+type route struct {
+	bus  Bus
+	gain float64
+}
+
+// Mixer — набор шин и подключённых к ним плееров
+type Mixer struct {
+	buses   map[Bus]*bus
+	players map[Player]route
+	rules   []DuckRule
+}
+
+// New создаёт микшер со всеми шинами на полной громкости и правилами DefaultDuckRules
+func New() *Mixer {
+	m := &Mixer{
+		buses:   make(map[Bus]*bus, len(Buses)),
+		players: make(map[Player]route),
+		rules:   append([]DuckRule(nil), DefaultDuckRules...),
+	}
+	for _, b := range Buses {
+		m.buses[b] = &bus{volume: 1, level: 1, duck: 1}
+	}
+	return m
+}
+
+func (m *Mixer) bus(b Bus) *bus {
+	if s, ok := m.buses[b]; ok {
+		return s
+	}
+	s := &bus{volume: 1, level: 1, duck: 1}
+	m.buses[b] = s
+	return s
+}
+
+// Attach направляет плеер в шину; gain — собственная громкость плеера
+func (m *Mixer) Attach(b Bus, p Player, gain float64) {
+	m.players[p] = route{bus: b, gain: gain}
+	m.apply(p)
+}
+
+// Detach отключает плеер от микшера; вызывается перед Close плеера
+func (m *Mixer) Detach(p Player) {
+	delete(m.players, p)
+}
+
+// SetGain меняет собственную громкость подключённого плеера
+func (m *Mixer) SetGain(p Player, gain float64) {
+	r, ok := m.players[p]
+	if !ok {
+		return
+	}
+	r.gain = gain
+	m.players[p] = r
+	m.apply(p)
+}
+
+// Volume возвращает громкость шины, выставленную игроком
+func (m *Mixer) Volume(b Bus) float64 {
+	return m.bus(b).volume
+}
+
+// SetVolume задаёт громкость шины 0..1
+func (m *Mixer) SetVolume(b Bus, v float64) {
+	m.bus(b).volume = clamp01(v)
+	m.applyAll()
+}
+
+// Muted сообщает, выключен ли звук шины
+func (m *Mixer) Muted(b Bus) bool {
+	return m.bus(b).muted
+}
+
+// SetMuted выключает или включает звук шины, не меняя её громкость
+func (m *Mixer) SetMuted(b Bus, muted bool) {
+	m.bus(b).muted = muted
+	m.applyAll()
+}
+
+// SetLevel задаёт уровень шины, зависящий от игры, а не от игрока:
+// например, музыка в настройках тише, чем в главном меню
+func (m *Mixer) SetLevel(b Bus, level float64) {
+	m.bus(b).level = clamp01(level)
+	m.applyAll()
+}
+
+// SetDuckRules заменяет правила приглушения
+func (m *Mixer) SetDuckRules(rules []DuckRule) {
+	m.rules = append([]DuckRule(nil), rules...)
+}
+
+// Effective возвращает итоговый множитель шины с учётом master
+func (m *Mixer) Effective(b Bus) float64 {
+	v := m.gainOf(b)
+	if b != Master {
+		v *= m.gainOf(Master)
+	}
+	return v
+}
+
+func (m *Mixer) gainOf(b Bus) float64 {
+	s := m.bus(b)
+	if s.muted {
+		return 0
+	}
+	return s.volume * s.level * s.duck
+}
+
+// playing сообщает, играет ли что-нибудь на шине
+func (m *Mixer) playing(b Bus) bool {
+	for p, r := range m.players {
+		if r.bus == b && p.IsPlaying() {
+			return true
+		}
+	}
+	return false
+}
+
+// Update плавно ведёт приглушение шин к целям правил и обновляет громкость
+// плееров; вызывается раз в тик
+func (m *Mixer) Update() {
+	targets := make(map[Bus]float64, len(m.buses))
+	for b := range m.buses {
+		targets[b] = 1
+	}
+	for _, r := range m.rules {
+		if m.playing(r.Trigger) {
+			targets[r.Target] = math.Min(targets[r.Target], r.Level)
+		}
+	}
+
+	changed := false
+	for b, target := range targets {
+		s := m.bus(b)
+		if s.duck == target {
+			continue
+		}
+		if s.duck < target {
+			s.duck = math.Min(target, s.duck+DuckSpeed)
+		} else {
+			s.duck = math.Max(target, s.duck-DuckSpeed)
+		}
+		changed = true
+	}
+	if changed {
+		m.applyAll()
+	}
+}
+
+func (m *Mixer) apply(p Player) {
+	r := m.players[p]
+	p.SetVolume(r.gain * m.Effective(r.bus))
+}
+
+func (m *Mixer) applyAll() {
+	for p := range m.players {
+		m.apply(p)
+	}
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
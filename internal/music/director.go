@@ -0,0 +1,409 @@
+// Package music управляет фоновой музыкой: плейлисты сцен, плавный переход
+// (crossfade) между ними, треки со вступлением и петлёй и продолжение трека
+// с того места, где он был прерван.
+package music
+
+import (
+	"aethelgard/internal/mixer"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// bytesPerSample — 16 бит на канал, два канала
+const bytesPerSample = 4
+
+// DefaultFade — длительность перехода между треками по умолчанию
+const DefaultFade = 2 * time.Second
+
+// Stream — декодированный поток PCM с известной длиной в байтах. Поток
+// читается по мере проигрывания и закрывается вместе с плеером.
+type Stream interface {
+	io.ReadSeeker
+	io.Closer
+	Length() int64
+}
+
+// Opener открывает и декодирует трек по пути в ресурсах
+type Opener func(path string) (Stream, error)
+
+// Player — то, что проигрывателю нужно от звукового плеера; *audio.Player
+// из ebiten подходит
+type Player interface {
+	mixer.Player
+	Play()
+	Current() time.Duration
+	Seek(offset time.Duration) error
+	Close() error
+}
+
+// Output создаёт плеер для потока PCM с частотой дискретизации проигрывателя
+type Output func(src io.ReadSeeker) (Player, error)
+
+// Track — трек плейлиста
+type Track struct {
+	Path string
+
+	// Loop — трек зацикливается, а не сменяется следующим. Единственный трек
+	// плейлиста зацикливается всегда.
+	Loop bool
+	// LoopStart — начало петли в сэмплах; всё до него — вступление, которое
+	// играет один раз
+	LoopStart int64
+	// LoopEnd — конец петли в сэмплах; 0 — конец файла
+	LoopEnd int64
+}
+
+// Playlist — набор треков, который играет, пока он выбран
+type Playlist struct {
+	Name    string
+	Tracks  []Track
+	Shuffle bool
+}
+
+// queue — порядок треков плейлиста и текущий трек; сохраняется при смене
+// плейлиста, чтобы при возврате играл тот же трек
+type queue struct {
+	playlist *Playlist
+	order    []int
+	pos      int
+}
+
+// voice — играющий трек со своей громкостью перехода
+type voice struct {
+	track  Track
+	player Player
+	stream Stream
+	loop   bool
+	length time.Duration // длительность трека без петли; 0 у зацикленных
+
+	gain   float64
+	target float64
+}
+
+// Director — проигрыватель музыки; все плееры идут через шину Music микшера
+type Director struct {
+	rate  int
+	out   Output
+	mixer *mixer.Mixer
+	open  Opener
+
+	fade   time.Duration
+	queues map[string]*queue
+	resume map[string]time.Duration
+	rand   *rand.Rand
+
+	current string
+	active  *voice
+	fading  []*voice
+}
+
+// NewDirector создаёт проигрыватель без плейлистов; sampleRate — частота
+// потоков, которые отдаёт open
+func NewDirector(sampleRate int, out Output, m *mixer.Mixer, open Opener) *Director {
+	return &Director{
+		rate:   sampleRate,
+		out:    out,
+		mixer:  m,
+		open:   open,
+		fade:   DefaultFade,
+		queues: make(map[string]*queue),
+		resume: make(map[string]time.Duration),
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SetFade задаёт длительность перехода; 0 — переключение без перехода
+func (d *Director) SetFade(fade time.Duration) {
+	if fade < 0 {
+		fade = 0
+	}
+	d.fade = fade
+}
+
+// Add регистрирует плейлист; плейлист с тем же именем заменяется
+func (d *Director) Add(p Playlist) {
+	p.Tracks = append([]Track(nil), p.Tracks...)
+	q := &queue{playlist: &p}
+	d.shuffle(q, -1)
+	d.queues[p.Name] = q
+}
+
+// Current возвращает имя играющего плейлиста или "", если музыка остановлена
+func (d *Director) Current() string {
+	return d.current
+}
+
+// Play плавно переключает музыку на плейлист. Если он уже играет, ничего
+// не происходит; трек, прерванный раньше, продолжится с того же места.
+func (d *Director) Play(name string) {
+	if name == d.current {
+		return
+	}
+	q, ok := d.queues[name]
+	if !ok {
+		log.Printf("Warning: music: unknown playlist %q", name)
+		return
+	}
+
+	d.current = name
+	d.fadeOut()
+	d.startQueue(q)
+}
+
+// Stop плавно останавливает музыку, запоминая позицию трека
+func (d *Director) Stop() {
+	d.current = ""
+	d.fadeOut()
+}
+
+// Update ведёт громкость переходов и сменяет доигравшие треки; вызывается
+// раз в тик, tick — длительность тика
+func (d *Director) Update(tick time.Duration) {
+	step := 1.0
+	if d.fade > 0 {
+		step = float64(tick) / float64(d.fade)
+	}
+
+	fading := d.fading[:0]
+	for _, v := range d.fading {
+		v.step(step)
+		d.mixer.SetGain(v.player, v.gain)
+		switch {
+		case !v.player.IsPlaying():
+			// Трек доиграл до конца, пока затихал
+			delete(d.resume, v.track.Path)
+			d.release(v)
+		case v.gain == 0:
+			d.resume[v.track.Path] = v.player.Current()
+			d.release(v)
+		default:
+			fading = append(fading, v)
+		}
+	}
+	for i := len(fading); i < len(d.fading); i++ {
+		d.fading[i] = nil
+	}
+	d.fading = fading
+
+	if d.active == nil {
+		return
+	}
+	v := d.active
+	v.step(step)
+	d.mixer.SetGain(v.player, v.gain)
+
+	// Трек без петли уходит в затихание заранее, чтобы следующий начался внахлёст
+	// (если трек не короче двух переходов)
+	ended := !v.player.IsPlaying() || (v.length > 2*d.fade && v.length-v.player.Current() <= d.fade)
+	if !v.loop && ended {
+		delete(d.resume, v.track.Path)
+		d.fadeOut()
+		if q, ok := d.queues[d.current]; ok {
+			d.advance(q)
+			d.startQueue(q)
+		}
+	}
+}
+
+// Close останавливает и закрывает все плееры
+func (d *Director) Close() {
+	if d.active != nil {
+		d.release(d.active)
+		d.active = nil
+	}
+	for _, v := range d.fading {
+		d.release(v)
+	}
+	d.fading = nil
+	d.current = ""
+}
+
+// fadeOut переводит текущий трек в затихающие
+func (d *Director) fadeOut() {
+	if d.active == nil {
+		return
+	}
+	d.active.target = 0
+	d.fading = append(d.fading, d.active)
+	d.active = nil
+}
+
+// startQueue запускает текущий трек очереди; треки, которые не удалось
+// открыть, пропускаются, пока не кончится плейлист
+func (d *Director) startQueue(q *queue) {
+	for range q.playlist.Tracks {
+		track := q.playlist.Tracks[q.order[q.pos]]
+		loop := track.Loop || len(q.playlist.Tracks) == 1
+		if err := d.start(track, loop); err != nil {
+			log.Printf("Warning: music: failed to play %s: %v", track.Path, err)
+			d.advance(q)
+			continue
+		}
+		return
+	}
+}
+
+// start делает трек активным. Если он ещё затихает, он возвращается
+// из затихания, иначе открывается заново с запомненной позиции.
+func (d *Director) start(track Track, loop bool) error {
+	for i, v := range d.fading {
+		if v.track.Path == track.Path && v.player.IsPlaying() {
+			d.fading = append(d.fading[:i], d.fading[i+1:]...)
+			v.target = 1
+			d.active = v
+			return nil
+		}
+	}
+
+	stream, err := d.open(track.Path)
+	if err != nil {
+		return err
+	}
+
+	v := &voice{track: track, stream: stream, loop: loop, target: 1}
+	var src io.ReadSeeker = stream
+	if loop {
+		intro := track.LoopStart * bytesPerSample
+		end := track.LoopEnd * bytesPerSample
+		if end <= 0 || end > stream.Length() {
+			end = stream.Length()
+		}
+		if intro < 0 || intro >= end {
+			log.Printf("Warning: music: bad loop points in %s, looping whole track", track.Path)
+			intro, end = 0, stream.Length()
+		}
+		src = &introLoop{src: stream, intro: intro, end: end}
+	} else {
+		v.length = time.Duration(stream.Length()/bytesPerSample) * time.Second / time.Duration(d.rate)
+	}
+
+	player, err := d.out(src)
+	if err != nil {
+		stream.Close()
+		return err
+	}
+	if pos, ok := d.resume[track.Path]; ok && (loop || pos < v.length) {
+		if err := player.Seek(pos); err != nil {
+			log.Printf("Warning: music: failed to resume %s at %v: %v", track.Path, pos, err)
+		}
+	}
+	v.player = player
+
+	// Без перехода трек сразу звучит на полную громкость
+	if d.fade == 0 {
+		v.gain = 1
+	}
+	d.mixer.Attach(mixer.Music, player, v.gain)
+	player.Play()
+	d.active = v
+	return nil
+}
+
+// advance переходит к следующему треку очереди, перемешивая её заново по кругу
+func (d *Director) advance(q *queue) {
+	q.pos++
+	if q.pos >= len(q.order) {
+		d.shuffle(q, q.order[len(q.order)-1])
+	}
+}
+
+// shuffle строит порядок треков; last — трек, игравший последним, он не
+// попадёт в начало нового круга
+func (d *Director) shuffle(q *queue, last int) {
+	n := len(q.playlist.Tracks)
+	q.pos = 0
+	if !q.playlist.Shuffle {
+		q.order = make([]int, n)
+		for i := range q.order {
+			q.order[i] = i
+		}
+		return
+	}
+	q.order = d.rand.Perm(n)
+	if n > 1 && q.order[0] == last {
+		j := 1 + d.rand.Intn(n-1)
+		q.order[0], q.order[j] = q.order[j], q.order[0]
+	}
+}
+
+func (d *Director) release(v *voice) {
+	d.mixer.Detach(v.player)
+	v.player.Close()
+	v.stream.Close()
+}
+
+// step сдвигает громкость перехода к цели. Тик короче своей доли секунды
+// на округление до наносекунд, и последний шаг добирает этот остаток,
+// чтобы переход занимал ровно fade, а не лишний тик.
+func (v *voice) step(step float64) {
+	switch diff := v.target - v.gain; {
+	case math.Abs(diff) <= step*(1+1e-6):
+		v.gain = v.target
+	case diff > 0:
+		v.gain += step
+	default:
+		v.gain -= step
+	}
+}
+
+// introLoop — поток, который играет вступление [0, intro) один раз, а затем
+// бесконечно повторяет петлю [intro, end); границы в байтах. Позиция потока
+// растёт без конца, как время проигрывания.
+type introLoop struct {
+	src        io.ReadSeeker
+	intro, end int64
+
+	pos int64 // позиция в бесконечном потоке
+	at  int64 // позиция в src
+}
+
+// source переводит позицию бесконечного потока в позицию src
+func (l *introLoop) source(pos int64) int64 {
+	if pos < l.end {
+		return pos
+	}
+	return l.intro + (pos-l.intro)%(l.end-l.intro)
+}
+
+func (l *introLoop) Read(b []byte) (int, error) {
+	at := l.source(l.pos)
+	if at != l.at {
+		if _, err := l.src.Seek(at, io.SeekStart); err != nil {
+			return 0, err
+		}
+		l.at = at
+	}
+	if rest := l.end - at; int64(len(b)) > rest {
+		b = b[:rest]
+	}
+
+	n, err := l.src.Read(b)
+	l.pos += int64(n)
+	l.at += int64(n)
+	if err == io.EOF {
+		if n == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		err = nil
+	}
+	return n, err
+}
+
+func (l *introLoop) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += l.pos
+	default:
+		return 0, errors.New("music: looped stream has no end")
+	}
+	if offset < 0 {
+		return 0, errors.New("music: negative position")
+	}
+	l.pos = offset
+	return offset, nil
+}
@@ -0,0 +1,360 @@
+package music
+
+import (
+	"aethelgard/internal/mixer"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// rate — частота потоков в тестах: низкая, чтобы треки были короткими,
+// и кратная частоте тиков, чтобы плеер за тик читал целое число сэмплов
+const rate = 1200
+
+const tick = time.Second / 60
+
+// pcm — поток PCM в памяти: каждый сэмпл хранит свой номер, чтобы по
+// прочитанному было видно, откуда он взят
+type pcm struct {
+	data   []byte
+	pos    int64
+	closed bool
+}
+
+func newPCM(seconds float64) *pcm {
+	n := int(seconds * rate)
+	s := &pcm{data: make([]byte, n*bytesPerSample)}
+	for i := 0; i < n; i++ {
+		s.data[i*bytesPerSample] = byte(i)
+		s.data[i*bytesPerSample+1] = byte(i >> 8)
+		s.data[i*bytesPerSample+2] = byte(i >> 16)
+	}
+	return s
+}
+
+// sampleAt возвращает номер сэмпла, записанный в b
+func sampleAt(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
+
+func (s *pcm) Read(b []byte) (int, error) {
+	if s.pos >= int64(len(s.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, s.data[s.pos:])
+	s.pos += int64(n)
+	return n, nil
+}
+
+func (s *pcm) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += s.pos
+	case io.SeekEnd:
+		offset += int64(len(s.data))
+	}
+	s.pos = offset
+	return offset, nil
+}
+
+func (s *pcm) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *pcm) Length() int64 {
+	return int64(len(s.data))
+}
+
+// player — плеер без звука: за тик читает из источника столько, сколько
+// проиграл бы настоящий, и останавливается, когда источник кончился
+type player struct {
+	src     io.ReadSeeker
+	read    int64
+	playing bool
+	closed  bool
+	volume  float64
+}
+
+func (p *player) SetVolume(v float64) { p.volume = v }
+func (p *player) IsPlaying() bool     { return p.playing }
+func (p *player) Play()               { p.playing = true }
+
+func (p *player) Current() time.Duration {
+	return time.Duration(p.read/bytesPerSample) * time.Second / rate
+}
+
+func (p *player) Seek(offset time.Duration) error {
+	pos := int64(offset*rate/time.Second) * bytesPerSample
+	if _, err := p.src.Seek(pos, io.SeekStart); err != nil {
+		return err
+	}
+	p.read = pos
+	return nil
+}
+
+func (p *player) Close() error {
+	p.closed, p.playing = true, false
+	return nil
+}
+
+func (p *player) advance(d time.Duration) {
+	if !p.playing {
+		return
+	}
+	buf := make([]byte, int64(math.Round(d.Seconds()*rate))*bytesPerSample)
+	n, err := io.ReadFull(p.src, buf)
+	p.read += int64(n)
+	if err != nil {
+		p.playing = false
+	}
+}
+
+// rig — проигрыватель с дорожками в памяти; считает, сколько раз открыт
+// каждый трек, и хранит все созданные плееры
+type rig struct {
+	*Director
+	tracks  map[string]float64 // длительность в секундах
+	opened  map[string]int
+	players []*player
+}
+
+func newRig(tracks map[string]float64) *rig {
+	r := &rig{tracks: tracks, opened: make(map[string]int)}
+	out := func(src io.ReadSeeker) (Player, error) {
+		p := &player{src: src}
+		r.players = append(r.players, p)
+		return p, nil
+	}
+	open := func(path string) (Stream, error) {
+		seconds, ok := r.tracks[path]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", path, errors.New("not found"))
+		}
+		r.opened[path]++
+		return newPCM(seconds), nil
+	}
+	r.Director = NewDirector(rate, out, mixer.New(), open)
+	r.rand = rand.New(rand.NewSource(1))
+	return r
+}
+
+// run проигрывает n тиков
+func (r *rig) run(n int) {
+	for i := 0; i < n; i++ {
+		r.Update(tick)
+		for _, p := range r.players {
+			p.advance(tick)
+		}
+	}
+}
+
+func (r *rig) playing() string {
+	if r.active == nil {
+		return ""
+	}
+	return r.active.track.Path
+}
+
+func near(a, b, eps float64) bool {
+	return math.Abs(a-b) <= eps
+}
+
+// TestCrossfade — при смене плейлиста старый трек затихает, а новый
+// нарастает за время перехода, после чего старый плеер закрывается
+func TestCrossfade(t *testing.T) {
+	r := newRig(map[string]float64{"a.ogg": 60, "b.ogg": 60})
+	r.SetFade(time.Second)
+	r.Add(Playlist{Name: "a", Tracks: []Track{{Path: "a.ogg"}}})
+	r.Add(Playlist{Name: "b", Tracks: []Track{{Path: "b.ogg"}}})
+
+	r.Play("a")
+	r.run(60)
+	if !near(r.active.gain, 1, 1e-6) {
+		t.Fatalf("gain after fade in = %v, want 1", r.active.gain)
+	}
+
+	r.Play("b")
+	old := r.players[0]
+	r.run(30)
+	if len(r.fading) != 1 || !near(r.fading[0].gain, 0.5, 0.02) || !near(r.active.gain, 0.5, 0.02) {
+		t.Errorf("halfway: fading %v, active %v; want both 0.5", r.fading, r.active.gain)
+	}
+	r.run(30)
+	if len(r.fading) != 0 || !near(r.active.gain, 1, 1e-6) {
+		t.Errorf("after fade: %d fading, active gain %v; want 0 and 1", len(r.fading), r.active.gain)
+	}
+	if !old.closed {
+		t.Error("faded out player was not closed")
+	}
+	if r.Current() != "b" || r.playing() != "b.ogg" {
+		t.Errorf("playing %s/%s, want b/b.ogg", r.Current(), r.playing())
+	}
+}
+
+// TestNoFade — без перехода трек сразу звучит на полную громкость
+func TestNoFade(t *testing.T) {
+	r := newRig(map[string]float64{"a.ogg": 10})
+	r.SetFade(0)
+	r.Add(Playlist{Name: "a", Tracks: []Track{{Path: "a.ogg"}}})
+	r.Play("a")
+	if r.active.gain != 1 {
+		t.Errorf("gain = %v, want 1", r.active.gain)
+	}
+}
+
+// TestIntroLoop — вступление играет один раз, дальше повторяется петля,
+// и переход к позиции внутри петли попадает в нужный сэмпл
+func TestIntroLoop(t *testing.T) {
+	src := newPCM(1)
+	l := &introLoop{src: src, intro: 200 * bytesPerSample, end: 800 * bytesPerSample}
+
+	buf := make([]byte, 2000*bytesPerSample)
+	if _, err := io.ReadFull(l, buf); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2000; i++ {
+		want := i
+		if i >= 800 {
+			want = 200 + (i-200)%600
+		}
+		if got := sampleAt(buf[i*bytesPerSample:]); got != want {
+			t.Fatalf("sample %d = %d, want %d", i, got, want)
+		}
+	}
+
+	if _, err := l.Seek(1500*bytesPerSample, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(l, buf[:bytesPerSample]); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sampleAt(buf), 200+(1500-200)%600; got != want {
+		t.Errorf("sample after seek = %d, want %d", got, want)
+	}
+	if _, err := l.Seek(0, io.SeekEnd); err == nil {
+		t.Error("seek from the end of an endless stream succeeded")
+	}
+}
+
+// TestLoopPoints — трек с петлёй играет дольше файла, а неверные точки
+// петли не мешают ему играть
+func TestLoopPoints(t *testing.T) {
+	r := newRig(map[string]float64{"a.ogg": 2, "b.ogg": 2})
+	r.Add(Playlist{Name: "a", Tracks: []Track{{Path: "a.ogg", Loop: true, LoopStart: rate / 2}}})
+	r.Add(Playlist{Name: "b", Tracks: []Track{{Path: "b.ogg", Loop: true, LoopStart: 5 * rate}}})
+
+	for _, name := range []string{"a", "b"} {
+		r.Play(name)
+		r.run(5 * 60)
+		if r.playing() != name+".ogg" || !r.active.player.IsPlaying() {
+			t.Errorf("%s stopped after 5s of a 2s looped track", name)
+		}
+		if cur := r.active.player.Current(); cur < 4*time.Second {
+			t.Errorf("%s position = %v, want past the end of the file", name, cur)
+		}
+	}
+}
+
+// TestPlaylistAdvance — трек без петли заранее уходит в затихание, и
+// следующий начинается внахлёст
+func TestPlaylistAdvance(t *testing.T) {
+	r := newRig(map[string]float64{"1.ogg": 5, "2.ogg": 5})
+	r.SetFade(time.Second)
+	r.Add(Playlist{Name: "p", Tracks: []Track{{Path: "1.ogg"}, {Path: "2.ogg"}}})
+
+	r.Play("p")
+	r.run(3*60 + 30)
+	if r.playing() != "1.ogg" {
+		t.Fatalf("playing %s at 3.5s, want 1.ogg", r.playing())
+	}
+	r.run(60)
+	if r.playing() != "2.ogg" || len(r.fading) != 1 {
+		t.Errorf("playing %s with %d fading at 4.5s, want 2.ogg over 1.ogg", r.playing(), len(r.fading))
+	}
+	r.run(5 * 60)
+	if r.playing() != "1.ogg" {
+		t.Errorf("playing %s after the second track, want 1.ogg again", r.playing())
+	}
+	if _, ok := r.resume["2.ogg"]; ok {
+		t.Error("finished track kept a resume position")
+	}
+}
+
+// TestShuffleRounds — каждый круг перемешанного плейлиста играет все треки
+// по разу, и трек на стыке кругов не повторяется
+func TestShuffleRounds(t *testing.T) {
+	r := newRig(nil)
+	tracks := make([]Track, 4)
+	for i := range tracks {
+		tracks[i] = Track{Path: fmt.Sprint(i)}
+	}
+	r.Add(Playlist{Name: "p", Shuffle: true, Tracks: tracks})
+	q := r.queues["p"]
+
+	prev := -1
+	for round := 0; round < 200; round++ {
+		seen := make(map[int]bool)
+		for i := range tracks {
+			cur := q.order[q.pos]
+			if cur == prev {
+				t.Fatalf("round %d, track %d: %d repeats", round, i, cur)
+			}
+			seen[cur] = true
+			prev = cur
+			r.advance(q)
+		}
+		if len(seen) != len(tracks) {
+			t.Fatalf("round %d played %d distinct tracks, want %d", round, len(seen), len(tracks))
+		}
+	}
+}
+
+// TestResume — прерванный трек продолжается с того места, где затих, а не
+// с начала; трек, который ещё затихает, возвращается без нового плеера
+func TestResume(t *testing.T) {
+	r := newRig(map[string]float64{"a.ogg": 60, "b.ogg": 60})
+	r.SetFade(time.Second)
+	r.Add(Playlist{Name: "a", Tracks: []Track{{Path: "a.ogg"}}})
+	r.Add(Playlist{Name: "b", Tracks: []Track{{Path: "b.ogg"}}})
+
+	r.Play("a")
+	r.run(5 * 60)
+	r.Play("b")
+	r.run(2 * 60)
+	pos, ok := r.resume["a.ogg"]
+	if !ok || !near(pos.Seconds(), 6, 0.05) {
+		t.Fatalf("resume position of a.ogg = %v (%v), want 6s", pos, ok)
+	}
+
+	r.Play("a")
+	if r.opened["a.ogg"] != 2 {
+		t.Fatalf("a.ogg opened %d times, want 2", r.opened["a.ogg"])
+	}
+	if cur := r.active.player.Current(); cur != pos {
+		t.Errorf("a.ogg resumed at %v, want %v", cur, pos)
+	}
+
+	// Возврат посреди затихания подхватывает тот же плеер
+	r.run(30)
+	r.Play("b")
+	r.run(15)
+	r.Play("a")
+	if r.opened["a.ogg"] != 2 || r.active.gain == 0 {
+		t.Errorf("a.ogg opened %d times, gain %v; want the fading player back", r.opened["a.ogg"], r.active.gain)
+	}
+}
+
+// TestMissingTrack — трек, который не открылся, пропускается
+func TestMissingTrack(t *testing.T) {
+	r := newRig(map[string]float64{"b.ogg": 10})
+	r.Add(Playlist{Name: "p", Tracks: []Track{{Path: "a.ogg"}, {Path: "b.ogg"}}})
+	r.Play("p")
+	if r.playing() != "b.ogg" {
+		t.Errorf("playing %q, want b.ogg", r.playing())
+	}
+}
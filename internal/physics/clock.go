@@ -0,0 +1,55 @@
+package physics
+
+import "time"
+
+// DefaultStep — шаг симуляции по умолчанию, 60 раз в секунду
+const DefaultStep = time.Second / 60
+
+// DefaultMaxSteps — сколько шагов догоняется за один вызов Advance, прежде
+// чем остаток времени отбрасывается
+const DefaultMaxSteps = 5
+
+// Clock — часы фиксированного шага: копят прошедшее время и отдают, сколько
+// целых шагов Step надо просчитать. Симуляция с одинаковым вводом даёт один
+// и тот же результат при любой частоте кадров и тиков. Время хранится
+// в целых наносекундах, чтобы остаток не накапливал ошибку округления.
+type Clock struct {
+	Step time.Duration
+	// MaxSteps ограничивает догоняние после долгого кадра, чтобы медленная
+	// машина не уходила в спираль всё более долгих кадров
+	MaxSteps int
+
+	acc time.Duration
+}
+
+// NewClock создаёт часы с шагом step
+func NewClock(step time.Duration) *Clock {
+	return &Clock{Step: step, MaxSteps: DefaultMaxSteps}
+}
+
+// Advance добавляет прошедшее время и возвращает число шагов к просчёту
+func (c *Clock) Advance(dt time.Duration) int {
+	c.acc += dt
+	n := int(c.acc / c.Step)
+	c.acc -= time.Duration(n) * c.Step
+	if c.MaxSteps > 0 && n > c.MaxSteps {
+		n = c.MaxSteps
+	}
+	return n
+}
+
+// Seconds возвращает шаг в секундах
+func (c *Clock) Seconds() float64 {
+	return c.Step.Seconds()
+}
+
+// Alpha возвращает, какую долю следующего шага уже прошло время: для
+// сглаживания отрисовки между двумя последними шагами
+func (c *Clock) Alpha() float64 {
+	return float64(c.acc) / float64(c.Step)
+}
+
+// Reset сбрасывает накопленное время
+func (c *Clock) Reset() {
+	c.acc = 0
+}
@@ -0,0 +1,214 @@
+// Package physics — столкновения прямоугольных хитбоксов с тайловой сеткой
+// и друг с другом и фиксированный шаг симуляции. Перемещение разбирается по
+// осям отдельно, поэтому упёршееся в стену тело скользит вдоль неё. Пакет
+// не зависит от ebiten и работает без окна.
+package physics
+
+import "math"
+
+// Rect — прямоугольник в пикселях мира; Max не входит в него
+type Rect struct {
+	MinX float64 `json:"min_x"`
+	MinY float64 `json:"min_y"`
+	MaxX float64 `json:"max_x"`
+	MaxY float64 `json:"max_y"`
+}
+
+// R — прямоугольник по двум углам
+func R(x0, y0, x1, y1 float64) Rect {
+	return Rect{x0, y0, x1, y1}
+}
+
+// W возвращает ширину прямоугольника
+func (r Rect) W() float64 {
+	return r.MaxX - r.MinX
+}
+
+// H возвращает высоту прямоугольника
+func (r Rect) H() float64 {
+	return r.MaxY - r.MinY
+}
+
+// Center возвращает центр прямоугольника
+func (r Rect) Center() (float64, float64) {
+	return (r.MinX + r.MaxX) / 2, (r.MinY + r.MaxY) / 2
+}
+
+// Translate сдвигает прямоугольник
+func (r Rect) Translate(dx, dy float64) Rect {
+	return Rect{r.MinX + dx, r.MinY + dy, r.MaxX + dx, r.MaxY + dy}
+}
+
+// Overlaps сообщает, что прямоугольники пересекаются; касание краями
+// пересечением не считается
+func (r Rect) Overlaps(o Rect) bool {
+	return r.MinX < o.MaxX && o.MinX < r.MaxX && r.MinY < o.MaxY && o.MinY < r.MaxY
+}
+
+// Grid — тайловая сетка с непроходимыми клетками
+type Grid interface {
+	Solid(tx, ty int) bool
+}
+
+// Body — хитбокс сущности в мире
+type Body struct {
+	ID   int
+	Rect Rect
+	// Solid — сквозь тело нельзя пройти; остальные тела только находятся
+	// запросами, например для взаимодействия
+	Solid bool
+}
+
+// Contact — на каких осях перемещение упёрлось в препятствие
+type Contact struct {
+	X, Y bool
+}
+
+// World — препятствия: тайловая сетка и хитбоксы сущностей
+type World struct {
+	Grid         Grid
+	TileW, TileH float64
+	Bodies       []Body
+
+	// CornerSlop — на сколько пикселей тело, упёршееся в угол препятствия
+	// при движении вдоль одной оси, может соскользнуть вбок, чтобы обойти его
+	CornerSlop float64
+}
+
+// Move сдвигает прямоугольник box на dx, dy и возвращает его новое
+// положение. Тело с идентификатором self не мешает самому себе. Путь
+// делится на отрезки не длиннее половины тайла и хитбокса, так что быстрое
+// тело не проскакивает сквозь тонкие стены.
+func (w *World) Move(box Rect, dx, dy float64, self int) (Rect, Contact) {
+	limit := math.Min(math.Min(w.TileW, w.TileH), math.Min(box.W(), box.H())) / 2
+	steps := 1
+	if limit > 0 {
+		steps = int(math.Ceil(math.Max(math.Abs(dx), math.Abs(dy)) / limit))
+		if steps < 1 {
+			steps = 1
+		}
+	}
+
+	var contact Contact
+	sx, sy := dx/float64(steps), dy/float64(steps)
+	for i := 0; i < steps; i++ {
+		var hit bool
+		if sx != 0 {
+			box, hit = w.moveX(box, sx, self)
+			if hit && sy == 0 {
+				box, hit = w.slideCorner(box, sx, self, true)
+			}
+			contact.X = contact.X || hit
+		}
+		if sy != 0 {
+			box, hit = w.moveY(box, sy, self)
+			if hit && sx == 0 {
+				box, hit = w.slideCorner(box, sy, self, false)
+			}
+			contact.Y = contact.Y || hit
+		}
+	}
+	return box, contact
+}
+
+// moveX сдвигает прямоугольник по горизонтали до первого препятствия
+func (w *World) moveX(box Rect, d float64, self int) (Rect, bool) {
+	next := box.Translate(d, 0)
+	hit := false
+	w.obstacles(next, box, self, func(o Rect) {
+		hit = true
+		if d > 0 {
+			next = next.Translate(math.Min(0, o.MinX-next.MaxX), 0)
+		} else {
+			next = next.Translate(math.Max(0, o.MaxX-next.MinX), 0)
+		}
+	})
+	return next, hit
+}
+
+// moveY сдвигает прямоугольник по вертикали до первого препятствия
+func (w *World) moveY(box Rect, d float64, self int) (Rect, bool) {
+	next := box.Translate(0, d)
+	hit := false
+	w.obstacles(next, box, self, func(o Rect) {
+		hit = true
+		if d > 0 {
+			next = next.Translate(0, math.Min(0, o.MinY-next.MaxY))
+		} else {
+			next = next.Translate(0, math.Max(0, o.MaxY-next.MinY))
+		}
+	})
+	return next, hit
+}
+
+// slideCorner сдвигает упёршееся тело поперёк движения, если в пределах
+// CornerSlop есть свободный проход: так тело не цепляется за углы. Сдвиг
+// за шаг не больше длины шага. Возвращает, осталось ли тело упёршимся.
+func (w *World) slideCorner(box Rect, d float64, self int, horizontal bool) (Rect, bool) {
+	for off := 1.0; off <= w.CornerSlop; off++ {
+		for _, sign := range [2]float64{-1, 1} {
+			var side, ahead Rect
+			if horizontal {
+				side = box.Translate(0, sign*off)
+				ahead = side.Translate(d, 0)
+			} else {
+				side = box.Translate(sign*off, 0)
+				ahead = side.Translate(0, d)
+			}
+			if w.Blocked(side, self) || w.Blocked(ahead, self) {
+				continue
+			}
+			nudge := sign * math.Min(off, math.Abs(d))
+			if horizontal {
+				return box.Translate(0, nudge), false
+			}
+			return box.Translate(nudge, 0), false
+		}
+	}
+	return box, true
+}
+
+// obstacles вызывает fn для каждого препятствия, пересекающего r. Препятствия,
+// которые уже пересекали исходное положение from, пропускаются, чтобы тело,
+// оказавшееся внутри стены, могло из неё выйти.
+func (w *World) obstacles(r, from Rect, self int, fn func(Rect)) {
+	if w.Grid != nil && w.TileW > 0 && w.TileH > 0 {
+		tx0, ty0 := int(math.Floor(r.MinX/w.TileW)), int(math.Floor(r.MinY/w.TileH))
+		tx1, ty1 := int(math.Ceil(r.MaxX/w.TileW)), int(math.Ceil(r.MaxY/w.TileH))
+		for ty := ty0; ty < ty1; ty++ {
+			for tx := tx0; tx < tx1; tx++ {
+				if !w.Grid.Solid(tx, ty) {
+					continue
+				}
+				t := R(float64(tx)*w.TileW, float64(ty)*w.TileH, float64(tx+1)*w.TileW, float64(ty+1)*w.TileH)
+				if t.Overlaps(r) && !t.Overlaps(from) {
+					fn(t)
+				}
+			}
+		}
+	}
+	for _, b := range w.Bodies {
+		if b.Solid && b.ID != self && b.Rect.Overlaps(r) && !b.Rect.Overlaps(from) {
+			fn(b.Rect)
+		}
+	}
+}
+
+// Blocked сообщает, что прямоугольник пересекает непроходимый тайл или
+// сплошное тело, кроме self
+func (w *World) Blocked(r Rect, self int) bool {
+	blocked := false
+	w.obstacles(r, Rect{}, self, func(Rect) { blocked = true })
+	return blocked
+}
+
+// Query возвращает тела, пересекающие прямоугольник, кроме self
+func (w *World) Query(r Rect, self int) []Body {
+	var out []Body
+	for _, b := range w.Bodies {
+		if b.ID != self && b.Rect.Overlaps(r) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
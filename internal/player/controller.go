@@ -0,0 +1,220 @@
+// Package player — управление ходящими персонажами, героем и жителями:
+// движение в восьми направлениях с разгоном и трением, столкновения со
+// стенами и хитбоксами сущностей со скольжением вдоль них и поиск того,
+// с чем можно взаимодействовать перед персонажем. Контроллер шагает
+// с фиксированным шагом и не зависит от ebiten.
+package player
+
+import (
+	"aethelgard/internal/physics"
+	"math"
+)
+
+// Direction — одно из восьми направлений взгляда, по часовой стрелке от «вниз»
+type Direction int
+
+const (
+	Down Direction = iota
+	DownLeft
+	Left
+	UpLeft
+	Up
+	UpRight
+	Right
+	DownRight
+)
+
+var directionNames = [...]string{"down", "down_left", "left", "up_left", "up", "up_right", "right", "down_right"}
+
+var directionVectors = [...][2]float64{
+	{0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}, {1, 0}, {1, 1},
+}
+
+func (d Direction) String() string {
+	if d < 0 || int(d) >= len(directionNames) {
+		return "unknown"
+	}
+	return directionNames[d]
+}
+
+// MarshalText записывает направление именем
+func (d Direction) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText читает направление по имени
+func (d *Direction) UnmarshalText(text []byte) error {
+	*d = ParseDirection(string(text))
+	return nil
+}
+
+// ParseDirection разбирает имя направления; неизвестное имя — Down
+func ParseDirection(s string) Direction {
+	for i, name := range directionNames {
+		if name == s {
+			return Direction(i)
+		}
+	}
+	return Down
+}
+
+// Vector возвращает единичный вектор направления
+func (d Direction) Vector() (float64, float64) {
+	v := directionVectors[d]
+	if v[0] != 0 && v[1] != 0 {
+		return v[0] * math.Sqrt2 / 2, v[1] * math.Sqrt2 / 2
+	}
+	return v[0], v[1]
+}
+
+// DirectionOf возвращает направление, ближайшее к вектору; для нулевого
+// вектора ok ложно
+func DirectionOf(x, y float64) (d Direction, ok bool) {
+	if x == 0 && y == 0 {
+		return Down, false
+	}
+	// Угол от «вниз» по часовой стрелке на экране (ось Y смотрит вниз)
+	a := math.Atan2(-x, y)
+	i := int(math.Round(a/(math.Pi/4))) % 8
+	if i < 0 {
+		i += 8
+	}
+	return Direction(i), true
+}
+
+// Настройки движения по умолчанию, в пикселях мира и секундах
+const (
+	DefaultMaxSpeed = 120.0
+	DefaultAccel    = 900.0
+	DefaultFriction = 1400.0
+	DefaultReach    = 20.0
+)
+
+// Controller — ходящий персонаж: положение его ног, скорость, направление
+// взгляда и хитбокс. Годится и для героя, и для жителей под управлением ИИ.
+type Controller struct {
+	// X, Y — точка у ног в пикселях мира. В мире сущностей положение
+	// хранит компонент позиции, а здесь лежит его рабочая копия на время
+	// шага, поэтому в JSON она не пишется.
+	X      float64   `json:"-"`
+	Y      float64   `json:"-"`
+	VX     float64   `json:"vx"`
+	VY     float64   `json:"vy"`
+	Facing Direction `json:"facing"`
+
+	// Hitbox — хитбокс относительно X, Y; у героя это ступни, а не весь
+	// спрайт, чтобы он мог зайти головой за край стены
+	Hitbox physics.Rect `json:"hitbox"`
+
+	// MaxSpeed — скорость ходьбы; Accel — разгон при нажатом направлении;
+	// Friction — торможение без него (пикселей в секунду за секунду)
+	MaxSpeed float64 `json:"max_speed"`
+	Accel    float64 `json:"accel"`
+	Friction float64 `json:"friction"`
+
+	// Reach — на сколько пикселей перед хитбоксом персонаж дотягивается
+	Reach float64 `json:"reach"`
+
+	// BodyID — тело персонажа в physics.World, чтобы оно не мешало самому себе
+	BodyID int `json:"-"`
+
+	prevX, prevY float64
+}
+
+// New создаёт персонажа в точке x, y с настройками по умолчанию
+func New(x, y float64) *Controller {
+	c := &Controller{
+		Hitbox:   physics.R(-10, -10, 10, 0),
+		MaxSpeed: DefaultMaxSpeed,
+		Accel:    DefaultAccel,
+		Friction: DefaultFriction,
+		Reach:    DefaultReach,
+		BodyID:   -1,
+	}
+	c.Place(x, y)
+	return c
+}
+
+// Place ставит персонажа в точку и останавливает его
+func (c *Controller) Place(x, y float64) {
+	c.X, c.Y = x, y
+	c.prevX, c.prevY = x, y
+	c.VX, c.VY = 0, 0
+}
+
+// Box возвращает хитбокс в координатах мира
+func (c *Controller) Box() physics.Rect {
+	return c.Hitbox.Translate(c.X, c.Y)
+}
+
+// Step просчитывает один шаг длиной dt секунд. ix, iy — направление ввода
+// из -1, 0, 1 по каждой оси; по диагонали скорость та же, что по прямой.
+func (c *Controller) Step(ix, iy, dt float64, w *physics.World) physics.Contact {
+	c.prevX, c.prevY = c.X, c.Y
+
+	if d, ok := DirectionOf(ix, iy); ok {
+		c.Facing = d
+		dx, dy := d.Vector()
+		c.VX, c.VY = approach(c.VX, c.VY, dx*c.MaxSpeed, dy*c.MaxSpeed, c.Accel*dt)
+	} else {
+		c.VX, c.VY = approach(c.VX, c.VY, 0, 0, c.Friction*dt)
+	}
+
+	box, contact := w.Move(c.Box(), c.VX*dt, c.VY*dt, c.BodyID)
+	c.X, c.Y = box.MinX-c.Hitbox.MinX, box.MinY-c.Hitbox.MinY
+
+	// Упёршись, персонаж теряет скорость поперёк стены, но не вдоль неё
+	if contact.X {
+		c.VX = 0
+	}
+	if contact.Y {
+		c.VY = 0
+	}
+	return contact
+}
+
+// approach сдвигает вектор (x, y) к (tx, ty) не дальше, чем на max
+func approach(x, y, tx, ty, max float64) (float64, float64) {
+	dx, dy := tx-x, ty-y
+	d := math.Hypot(dx, dy)
+	if d <= max || d == 0 {
+		return tx, ty
+	}
+	return x + dx/d*max, y + dy/d*max
+}
+
+// Interpolated возвращает положение для отрисовки между предыдущим и
+// текущим шагом; alpha — доля, прошедшая с последнего шага
+func (c *Controller) Interpolated(alpha float64) (float64, float64) {
+	return c.prevX + (c.X-c.prevX)*alpha, c.prevY + (c.Y-c.prevY)*alpha
+}
+
+// ReachRect возвращает область перед персонажем, в которой он может
+// взаимодействовать с предметами
+func (c *Controller) ReachRect() physics.Rect {
+	box := c.Box()
+	cx, cy := box.Center()
+	dx, dy := c.Facing.Vector()
+	hw, hh := box.W()/2, box.H()/2
+	// Центр области — у края хитбокса со стороны взгляда плюс половина досягаемости
+	px, py := cx+dx*(hw+c.Reach/2), cy+dy*(hh+c.Reach/2)
+	return physics.R(px-c.Reach/2, py-c.Reach/2, px+c.Reach/2, py+c.Reach/2)
+}
+
+// Interactable возвращает ближайшее к персонажу тело из тех, что в пределах
+// досягаемости и для которых accept истинно
+func (c *Controller) Interactable(w *physics.World, accept func(physics.Body) bool) (physics.Body, bool) {
+	cx, cy := c.Box().Center()
+	var best physics.Body
+	bestDist := math.Inf(1)
+	for _, b := range w.Query(c.ReachRect(), c.BodyID) {
+		if accept != nil && !accept(b) {
+			continue
+		}
+		bx, by := b.Rect.Center()
+		if d := math.Hypot(bx-cx, by-cy); d < bestDist {
+			best, bestDist = b, d
+		}
+	}
+	return best, !math.IsInf(bestDist, 1)
+}
@@ -0,0 +1,165 @@
+package save
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Формат файла начиная с версии 2:
+//
+//	magic    4 байта  "AGSV"
+//	checksum 4 байта  CRC-32 (IEEE) сжатых данных, big-endian
+//	payload           gzip(JSON File)
+//
+// Файлы версии 1 — несжатый JSON без заголовка.
+var magic = []byte("AGSV")
+
+const headerLen = 8
+
+// ErrCorrupt — файл повреждён: не сходится контрольная сумма или данные
+// не разбираются
+var ErrCorrupt = errors.New("save: file is corrupted")
+
+// ErrTooNew — файл записан более новой версией игры
+var ErrTooNew = errors.New("save: written by a newer version of the game")
+
+// migration переводит сырые данные файла с версии N на N+1
+type migration func(raw map[string]interface{}) error
+
+// migrations[N] обновляет формат версии N до N+1
+var migrations = map[int]migration{
+	1: migrateV1,
+}
+
+// migrateV1 переносит state.level в state.player.level: в версии 2
+// у персонажа игрока появилась своя запись
+func migrateV1(raw map[string]interface{}) error {
+	state, _ := raw["state"].(map[string]interface{})
+	if state == nil {
+		return errors.New("missing state")
+	}
+	player, _ := state["player"].(map[string]interface{})
+	if player == nil {
+		player = map[string]interface{}{}
+	}
+	if level, ok := state["level"]; ok {
+		player["level"] = level
+		delete(state, "level")
+	}
+	state["player"] = player
+	return nil
+}
+
+// encode сжимает файл и дописывает заголовок с контрольной суммой
+func encode(f File) ([]byte, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(magic)
+	buf.Write(make([]byte, 4))
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	out := buf.Bytes()
+	binary.BigEndian.PutUint32(out[4:headerLen], crc32.ChecksumIEEE(out[headerLen:]))
+	return out, nil
+}
+
+// decode проверяет контрольную сумму, распаковывает файл и поднимает его
+// версию цепочкой миграций до CurrentVersion
+func decode(data []byte) (File, error) {
+	payload, err := unpack(data)
+	if err != nil {
+		return File{}, err
+	}
+
+	raw := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return File{}, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	// null разбирается без ошибки, но оставляет карту пустой
+	if raw == nil {
+		return File{}, fmt.Errorf("%w: empty file", ErrCorrupt)
+	}
+
+	version := 0
+	if v, ok := raw["version"].(json.Number); ok {
+		n, err := v.Int64()
+		if err != nil {
+			return File{}, fmt.Errorf("%w: bad version %s", ErrCorrupt, v)
+		}
+		version = int(n)
+	}
+	if version > CurrentVersion {
+		return File{}, fmt.Errorf("%w (format %d)", ErrTooNew, version)
+	}
+
+	for ; version < CurrentVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return File{}, fmt.Errorf("%w: no migration from format version %d", ErrCorrupt, version)
+		}
+		if err := migrate(raw); err != nil {
+			return File{}, fmt.Errorf("%w: migrate from version %d: %v", ErrCorrupt, version, err)
+		}
+	}
+	raw["version"] = version
+
+	// Перекодируем мигрированную карту, чтобы разобрать её в структуру
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return File{}, fmt.Errorf("re-encode: %w", err)
+	}
+	var f File
+	if err := json.Unmarshal(migrated, &f); err != nil {
+		return File{}, fmt.Errorf("%w: decode: %v", ErrCorrupt, err)
+	}
+	return f, nil
+}
+
+// unpack возвращает JSON файла: проверяет заголовок и контрольную сумму
+// и распаковывает данные. Файл без заголовка читается как JSON версии 1.
+func unpack(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, magic) {
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("%w: unknown format", ErrCorrupt)
+		}
+		return data, nil
+	}
+
+	if len(data) < headerLen {
+		return nil, fmt.Errorf("%w: truncated header", ErrCorrupt)
+	}
+	sum := binary.BigEndian.Uint32(data[4:headerLen])
+	payload := data[headerLen:]
+	if crc32.ChecksumIEEE(payload) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package save
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestDecodeFixtures читает сохранения из testdata: старые форматы должны
+// подниматься миграциями до текущего, испорченные файлы — давать ErrCorrupt
+func TestDecodeFixtures(t *testing.T) {
+	tests := []struct {
+		file     string
+		err      error
+		level    int
+		playTime time.Duration
+	}{
+		{file: "v1_manual_1.sav", level: 3, playTime: time.Hour + 15*time.Minute + 12*time.Second},
+		{file: "v2_quick.sav", level: 4, playTime: 2*time.Hour + time.Minute},
+		{file: "corrupt_checksum.sav", err: ErrCorrupt},
+		{file: "truncated.sav", err: ErrCorrupt},
+		{file: "future_version.sav", err: ErrTooNew},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := decode(data)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("decode error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+
+			if f.Version != CurrentVersion {
+				t.Errorf("Version = %d, want %d", f.Version, CurrentVersion)
+			}
+			if f.State.Player.Level != tt.level {
+				t.Errorf("State.Player.Level = %d, want %d", f.State.Player.Level, tt.level)
+			}
+			if f.Meta.Level != tt.level {
+				t.Errorf("Meta.Level = %d, want %d", f.Meta.Level, tt.level)
+			}
+			if f.State.PlayTime != tt.playTime {
+				t.Errorf("State.PlayTime = %v, want %v", f.State.PlayTime, tt.playTime)
+			}
+			if f.State.Location != "Old Road" {
+				t.Errorf("State.Location = %q, want %q", f.State.Location, "Old Road")
+			}
+			if !f.State.Flags["met_ferryman"] {
+				t.Errorf("flag met_ferryman is not set: %v", f.State.Flags)
+			}
+		})
+	}
+}
+
+// TestDecodeMalformed — файлы, которые разбираются как JSON, но не как
+// сохранение, тоже повреждены
+func TestDecodeMalformed(t *testing.T) {
+	for _, data := range []string{
+		`null`,
+		`{}`,
+		`{"version":1}`,
+		`{"version":1,"state":[]}`,
+		`{"version":"two"}`,
+		`{"version":2,"state":{"player":{"level":"high"}}}`,
+		`not json`,
+	} {
+		if _, err := decode([]byte(data)); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("decode(%s) error = %v, want ErrCorrupt", data, err)
+		}
+	}
+}
+
+// TestEncodeRoundTrip — записанное читается обратно без потерь
+func TestEncodeRoundTrip(t *testing.T) {
+	in := File{
+		Version: CurrentVersion,
+		Meta:    Meta{Location: "Old Road", Level: 5, PlayTime: time.Minute},
+		State: State{
+			Location: "Old Road",
+			PlayTime: time.Minute,
+			Player:   Player{Level: 5, Position: &Position{X: 12, Y: 34, Facing: "left"}},
+			Flags:    map[string]bool{"met_ferryman": true},
+		},
+	}
+	data, err := encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := decode(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out.State.Player.Level != 5 || out.State.Player.Position == nil || *out.State.Player.Position != *in.State.Player.Position {
+		t.Errorf("player = %+v, want %+v", out.State.Player, in.State.Player)
+	}
+	if !out.State.Flags["met_ferryman"] || out.Meta.PlayTime != time.Minute {
+		t.Errorf("file = %+v, want %+v", out, in)
+	}
+
+	// Любой испорченный байт сжатых данных ловит контрольная сумма
+	data[len(data)-1] ^= 0xFF
+	if _, err := decode(data); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("decode of damaged file error = %v, want ErrCorrupt", err)
+	}
+}
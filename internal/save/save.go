@@ -0,0 +1,308 @@
+// Package save хранит сохранения игры в каталоге пользовательских данных:
+// ручные слоты, кольцо автосохранений и быстрое сохранение. Каждый файл
+// содержит версию формата, метаданные для экрана загрузки (время игры,
+// локация, уровень, дата, миниатюра) и полное состояние игры. Файлы
+// сжимаются, защищены контрольной суммой и записываются атомарно; старые
+// версии формата поднимаются при чтении цепочкой миграций.
+package save
+
+import (
+	"aethelgard/internal/atomicfile"
+	"aethelgard/internal/ecs"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurrentVersion — версия формата, которую пишет эта сборка
+const CurrentVersion = 2
+
+// Число слотов каждого вида
+const (
+	ManualSlots = 6
+	AutoSlots   = 3
+)
+
+// ext — расширение файлов сохранений
+const ext = ".sav"
+
+// asideExt дописывается к нечитаемому автосохранению, которое убрано
+// с дороги нового; такие файлы List не показывает
+const asideExt = ".bad"
+
+// ErrNotFound — в слоте нет сохранения
+var ErrNotFound = errors.New("save: slot is empty")
+
+// Kind — вид слота
+type Kind string
+
+const (
+	Manual Kind = "manual"
+	Auto   Kind = "auto"
+	Quick  Kind = "quick"
+)
+
+// Slot — слот сохранения; Index считается с 1, у быстрого сохранения он 0
+type Slot struct {
+	Kind  Kind
+	Index int
+}
+
+// ManualSlot возвращает ручной слот с номером i
+func ManualSlot(i int) Slot {
+	return Slot{Kind: Manual, Index: i}
+}
+
+// QuickSlot — слот быстрого сохранения
+var QuickSlot = Slot{Kind: Quick}
+
+// Name — имя слота, оно же имя файла без расширения: manual_1, auto_2, quick
+func (s Slot) Name() string {
+	if s.Kind == Quick {
+		return string(Quick)
+	}
+	return string(s.Kind) + "_" + strconv.Itoa(s.Index)
+}
+
+func (s Slot) String() string {
+	return s.Name()
+}
+
+// valid сообщает, что слот существует
+func (s Slot) valid() bool {
+	switch s.Kind {
+	case Manual:
+		return s.Index >= 1 && s.Index <= ManualSlots
+	case Auto:
+		return s.Index >= 1 && s.Index <= AutoSlots
+	case Quick:
+		return s.Index == 0
+	}
+	return false
+}
+
+// ParseSlot разбирает имя слота
+func ParseSlot(name string) (Slot, bool) {
+	if name == string(Quick) {
+		return QuickSlot, true
+	}
+	kind, index, ok := strings.Cut(name, "_")
+	if !ok {
+		return Slot{}, false
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return Slot{}, false
+	}
+	s := Slot{Kind: Kind(kind), Index: i}
+	return s, s.valid()
+}
+
+// State — состояние игры, которое восстанавливается при загрузке
+type State struct {
+	// Location — ключ локализации текущей локации
+	Location string        `json:"location"`
+	PlayTime time.Duration `json:"play_time"`
+	Player   Player        `json:"player"`
+
+	// Flags — сюжетные флаги
+	Flags map[string]bool `json:"flags,omitempty"`
+
+	// Entities — сущности мира; в сохранениях до их появления его нет,
+	// и мир заново заселяется с карты
+	Entities *ecs.Snapshot `json:"entities,omitempty"`
+}
+
+// Player — персонаж игрока
+type Player struct {
+	Level int `json:"level"`
+
+	// Position — где стоит герой; в старых сохранениях его нет, и герой
+	// появляется в начальной точке карты
+	Position *Position `json:"position,omitempty"`
+}
+
+// Position — положение героя на карте
+type Position struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Facing string  `json:"facing,omitempty"`
+}
+
+// Meta — сведения о сохранении для экрана загрузки
+type Meta struct {
+	Slot    Slot      `json:"-"`
+	SavedAt time.Time `json:"saved_at"`
+
+	PlayTime time.Duration `json:"play_time"`
+	Location string        `json:"location"`
+	Level    int           `json:"level"`
+
+	// Thumbnail — миниатюра кадра в PNG; может отсутствовать
+	Thumbnail []byte `json:"thumbnail,omitempty"`
+
+	// Err — файл в слоте есть, но прочитать его не удалось
+	Err error `json:"-"`
+}
+
+// File — содержимое файла сохранения
+type File struct {
+	Version int   `json:"version"`
+	Meta    Meta  `json:"meta"`
+	State   State `json:"state"`
+}
+
+// Store — каталог сохранений
+type Store struct {
+	dir string
+}
+
+// NewStore открывает каталог сохранений; он создаётся при первой записи
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir возвращает каталог сохранений внутри каталога данных игры
+func Dir(dataDir string) string {
+	return filepath.Join(dataDir, "saves")
+}
+
+func (s *Store) path(slot Slot) string {
+	return filepath.Join(s.dir, slot.Name()+ext)
+}
+
+// Save записывает состояние в слот; thumbnail — миниатюра в PNG или nil
+func (s *Store) Save(slot Slot, state State, thumbnail []byte) (Meta, error) {
+	if !slot.valid() {
+		return Meta{}, fmt.Errorf("save: invalid slot %v", slot)
+	}
+
+	f := File{
+		Version: CurrentVersion,
+		Meta: Meta{
+			Slot:      slot,
+			SavedAt:   time.Now(),
+			PlayTime:  state.PlayTime,
+			Location:  state.Location,
+			Level:     state.Player.Level,
+			Thumbnail: thumbnail,
+		},
+		State: state,
+	}
+	data, err := encode(f)
+	if err != nil {
+		return Meta{}, fmt.Errorf("save: encode %v: %w", slot, err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return Meta{}, fmt.Errorf("save: create %s: %w", s.dir, err)
+	}
+	if err := atomicfile.WriteFile(s.path(slot), data); err != nil {
+		return Meta{}, fmt.Errorf("save: write %v: %w", slot, err)
+	}
+	return f.Meta, nil
+}
+
+// Autosave записывает состояние в свободный слот автосохранения, а если
+// свободных нет — поверх самого старого. Слот с нечитаемым файлом (он
+// повреждён или записан более новой игрой) тоже считается свободным, но
+// файл сначала переименовывается в auto_N.sav.bad, чтобы его можно было
+// спасти.
+func (s *Store) Autosave(state State, thumbnail []byte) (Meta, error) {
+	slot := Slot{Kind: Auto, Index: 1}
+	var oldest time.Time
+	for i := 1; i <= AutoSlots; i++ {
+		candidate := Slot{Kind: Auto, Index: i}
+		f, err := s.Load(candidate)
+		if errors.Is(err, ErrNotFound) {
+			slot = candidate
+			break
+		}
+		if err != nil {
+			aside := s.path(candidate) + asideExt
+			if rerr := os.Rename(s.path(candidate), aside); rerr != nil {
+				return Meta{}, fmt.Errorf("save: set aside unreadable %v: %w", candidate, rerr)
+			}
+			log.Printf("Warning: %v; moved to %s before autosaving over it", err, aside)
+			slot = candidate
+			break
+		}
+		if oldest.IsZero() || f.Meta.SavedAt.Before(oldest) {
+			slot, oldest = candidate, f.Meta.SavedAt
+		}
+	}
+	return s.Save(slot, state, thumbnail)
+}
+
+// Load читает сохранение из слота; пустой слот возвращает ErrNotFound,
+// повреждённый — ErrCorrupt, записанный более новой игрой — ErrTooNew
+func (s *Store) Load(slot Slot) (File, error) {
+	data, err := os.ReadFile(s.path(slot))
+	if errors.Is(err, os.ErrNotExist) {
+		return File{}, ErrNotFound
+	}
+	if err != nil {
+		return File{}, fmt.Errorf("save: read %v: %w", slot, err)
+	}
+
+	f, err := decode(data)
+	if err != nil {
+		return File{}, fmt.Errorf("save: %v: %w", slot, err)
+	}
+	f.Meta.Slot = slot
+	return f, nil
+}
+
+// List возвращает сведения обо всех сохранениях, новые первыми. Файлы,
+// которые не удалось прочитать, попадают в конец списка с заполненным Err.
+func (s *Store) List() ([]Meta, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("save: list %s: %w", s.dir, err)
+	}
+
+	var list []Meta
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || filepath.Ext(name) != ext {
+			continue
+		}
+		slot, ok := ParseSlot(strings.TrimSuffix(name, ext))
+		if !ok {
+			continue
+		}
+		f, err := s.Load(slot)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			list = append(list, Meta{Slot: slot, Err: err})
+			continue
+		}
+		list = append(list, f.Meta)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if (list[i].Err == nil) != (list[j].Err == nil) {
+			return list[i].Err == nil
+		}
+		return list[i].SavedAt.After(list[j].SavedAt)
+	})
+	return list, nil
+}
+
+// Delete удаляет сохранение из слота
+func (s *Store) Delete(slot Slot) error {
+	err := os.Remove(s.path(slot))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("save: delete %v: %w", slot, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package save
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAutosaveSetsAsideUnreadable — автосохранение занимает слот с
+// испорченным файлом, но сам файл остаётся рядом под другим именем
+func TestAutosaveSetsAsideUnreadable(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir)
+	if _, err := s.Save(Slot{Kind: Auto, Index: 1}, State{Location: "Old Road"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	broken := []byte("AGSV\x00\x00\x00\x00broken")
+	if err := os.WriteFile(filepath.Join(dir, "auto_2.sav"), broken, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := s.Autosave(State{Location: "Ferry"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Slot{Kind: Auto, Index: 2}); meta.Slot != want {
+		t.Errorf("autosaved to %v, want %v", meta.Slot, want)
+	}
+
+	aside, err := os.ReadFile(filepath.Join(dir, "auto_2.sav.bad"))
+	if err != nil {
+		t.Fatalf("unreadable save was not set aside: %v", err)
+	}
+	if string(aside) != string(broken) {
+		t.Errorf("set-aside file = %q, want %q", aside, broken)
+	}
+	if f, err := s.Load(meta.Slot); err != nil || f.State.Location != "Ferry" {
+		t.Errorf("Load(%v) = %q, %v; want the new autosave", meta.Slot, f.State.Location, err)
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Errorf("List returned %d saves, want 2 (set-aside file must be hidden)", len(list))
+	}
+}
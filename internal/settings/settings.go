@@ -0,0 +1,206 @@
+// Package settings хранит пользовательские настройки в TOML-файле в каталоге
+// конфигурации пользователя: загрузка с миграцией старых версий схемы,
+// проверка значений и атомарная запись.
+package settings
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CurrentVersion — версия схемы, которую пишет эта сборка
+const CurrentVersion = 2
+
+// DefaultLanguage — язык первого запуска
+const DefaultLanguage = "ru"
+
+// Settings — содержимое файла настроек
+type Settings struct {
+	Version  int    `toml:"version"`
+	Language string `toml:"language"`
+
+	Audio   Audio   `toml:"audio"`
+	Display Display `toml:"display"`
+
+	// Controls — переназначенные привязки: действие → список вида "key:Enter"
+	Controls map[string][]string `toml:"controls,omitempty"`
+}
+
+// Audio — настройки звука по шинам микшера: master, music, sfx, voice, ambience
+type Audio struct {
+	Buses map[string]Bus `toml:"buses"`
+}
+
+// Bus — громкость и выключение звука одной шины
+type Bus struct {
+	Volume float64 `toml:"volume"`
+	Muted  bool    `toml:"muted"`
+}
+
+// Режимы окна
+const (
+	WindowWindowed   = "windowed"
+	WindowBorderless = "borderless"
+	WindowFullscreen = "fullscreen"
+)
+
+// Способы масштабирования кадра под окно
+const (
+	ScalingInteger   = "integer"   // целый масштаб, пиксель в пиксель
+	ScalingLetterbox = "letterbox" // с сохранением пропорций и полосами
+	ScalingStretch   = "stretch"   // растягивание на всё окно
+)
+
+// Display — режим окна, размер окна в оконном режиме, вертикальная
+// синхронизация, ограничение частоты кадров, масштабирование кадра
+// и масштаб интерфейса
+type Display struct {
+	Mode    string  `toml:"mode"`
+	Width   int     `toml:"width"`
+	Height  int     `toml:"height"`
+	VSync   bool    `toml:"vsync"`
+	FPSCap  int     `toml:"fps_cap"` // 0 — без ограничения
+	Scaling string  `toml:"scaling"`
+	UIScale float64 `toml:"ui_scale"`
+}
+
+// MaxFPSCap — верхняя граница ограничения частоты кадров
+const MaxFPSCap = 1000
+
+// Пределы масштаба интерфейса
+const (
+	MinUIScale = 0.5
+	MaxUIScale = 2.0
+)
+
+// DefaultBuses — громкость шин при первом запуске
+var DefaultBuses = map[string]float64{
+	"master":   0.7,
+	"music":    0.8,
+	"sfx":      1.0,
+	"voice":    1.0,
+	"ambience": 0.8,
+}
+
+// Defaults возвращает настройки первого запуска
+func Defaults() Settings {
+	return Settings{
+		Version:  CurrentVersion,
+		Language: DefaultLanguage,
+		Audio:    defaultAudio(),
+		Display: Display{
+			Mode:    WindowFullscreen,
+			Width:   1280,
+			Height:  720,
+			VSync:   true,
+			Scaling: ScalingLetterbox,
+			UIScale: 1,
+		},
+	}
+}
+
+func defaultAudio() Audio {
+	a := Audio{Buses: make(map[string]Bus, len(DefaultBuses))}
+	for name, v := range DefaultBuses {
+		a.Buses[name] = Bus{Volume: v}
+	}
+	return a
+}
+
+// Validate приводит значения к допустимым диапазонам; недопустимые
+// значения без диапазона и NaN заменяются значениями по умолчанию. Версию
+// новее CurrentVersion Validate не трогает: такой файл не перезаписывается.
+func (s *Settings) Validate() {
+	def := Defaults()
+
+	if s.Version < CurrentVersion {
+		s.Version = CurrentVersion
+	}
+
+	// Шины, которых нет в файле, получают громкость по умолчанию
+	if s.Audio.Buses == nil {
+		s.Audio.Buses = make(map[string]Bus, len(DefaultBuses))
+	}
+	for name, v := range DefaultBuses {
+		if _, ok := s.Audio.Buses[name]; !ok {
+			s.Audio.Buses[name] = Bus{Volume: v}
+		}
+	}
+	for name, b := range s.Audio.Buses {
+		if math.IsNaN(b.Volume) {
+			// У шины, которой нет в DefaultBuses, громкость по умолчанию полная
+			v, ok := DefaultBuses[name]
+			if !ok {
+				v = 1
+			}
+			b.Volume = v
+		}
+		b.Volume = clamp01(b.Volume)
+		s.Audio.Buses[name] = b
+	}
+
+	d := &s.Display
+	switch d.Mode {
+	case WindowWindowed, WindowBorderless, WindowFullscreen:
+	default:
+		d.Mode = def.Display.Mode
+	}
+	switch d.Scaling {
+	case ScalingInteger, ScalingLetterbox, ScalingStretch:
+	default:
+		d.Scaling = def.Display.Scaling
+	}
+	if d.Width < 320 || d.Height < 180 {
+		d.Width, d.Height = def.Display.Width, def.Display.Height
+	}
+	if d.FPSCap < 0 {
+		d.FPSCap = 0
+	}
+	if d.FPSCap > MaxFPSCap {
+		d.FPSCap = MaxFPSCap
+	}
+	if d.UIScale == 0 || math.IsNaN(d.UIScale) {
+		d.UIScale = def.Display.UIScale
+	}
+	d.UIScale = math.Max(MinUIScale, math.Min(MaxUIScale, d.UIScale))
+
+	// Доступность языка проверяет игра: список языков задают файлы каталогов
+	if strings.TrimSpace(s.Language) == "" {
+		s.Language = def.Language
+	}
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// Path возвращает путь к файлу настроек: $XDG_CONFIG_HOME/aethelgard/settings.toml,
+// а без XDG_CONFIG_HOME — каталог конфигурации пользователя для текущей ОС
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings.toml"), nil
+}
+
+// Dir возвращает каталог пользовательских данных игры: настройки, моды
+func Dir() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		dir, err = os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "aethelgard"), nil
+}
@@ -0,0 +1,155 @@
+package settings
+
+import (
+	"aethelgard/internal/atomicfile"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ErrTooNew — файл записан более новой версией игры. Известные поля из него
+// читаются, но перезаписывать его нельзя: новая версия потеряла бы свои поля.
+var ErrTooNew = errors.New("settings: written by a newer version of the game")
+
+// migration переводит сырые данные файла с версии N на N+1
+type migration func(raw map[string]interface{}) error
+
+// migrations[N] обновляет схему версии N до N+1
+var migrations = map[int]migration{
+	0: migrateV0,
+	1: migrateV1,
+}
+
+// migrateV0 переводит файл без версии (плоские ключи master_volume и
+// language, язык числом, как он хранился в Game) в схему версии 1
+func migrateV0(raw map[string]interface{}) error {
+	audio, _ := raw["audio"].(map[string]interface{})
+	if audio == nil {
+		audio = map[string]interface{}{}
+	}
+	if v, ok := raw["master_volume"]; ok {
+		audio["master_volume"] = v
+		delete(raw, "master_volume")
+	}
+	raw["audio"] = audio
+
+	if lang, ok := raw["language"].(int64); ok {
+		switch lang {
+		case 1:
+			raw["language"] = "en"
+		default:
+			raw["language"] = "ru"
+		}
+	}
+	return nil
+}
+
+// migrateV1 переносит audio.master_volume в шину master: в версии 2
+// громкость задаётся по шинам микшера
+func migrateV1(raw map[string]interface{}) error {
+	audio, _ := raw["audio"].(map[string]interface{})
+	if audio == nil {
+		return nil
+	}
+
+	if v, ok := audio["master_volume"]; ok {
+		buses, _ := audio["buses"].(map[string]interface{})
+		if buses == nil {
+			buses = map[string]interface{}{}
+		}
+		buses["master"] = map[string]interface{}{"volume": v}
+		audio["buses"] = buses
+		delete(audio, "master_volume")
+	}
+	return nil
+}
+
+// Load читает настройки из файла. Отсутствующий файл — не ошибка: возвращаются
+// значения по умолчанию. Повреждённый файл возвращает значения по умолчанию
+// вместе с ошибкой, чтобы вызывающий код мог записать предупреждение. Файл
+// более новой версии возвращает прочитанные значения и ErrTooNew.
+func Load(path string) (Settings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Defaults(), nil
+	}
+	if err != nil {
+		return Defaults(), fmt.Errorf("settings: read %s: %w", path, err)
+	}
+
+	s, err := decode(data)
+	if err != nil {
+		return Defaults(), fmt.Errorf("settings: %s: %w", path, err)
+	}
+	if s.Version > CurrentVersion {
+		return s, fmt.Errorf("%w: %s (version %d)", ErrTooNew, path, s.Version)
+	}
+	return s, nil
+}
+
+// decode разбирает файл, применяет миграции и проверку значений
+func decode(data []byte) (Settings, error) {
+	raw := map[string]interface{}{}
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return Settings{}, fmt.Errorf("parse: %w", err)
+	}
+
+	version := 0
+	if v, ok := raw["version"].(int64); ok {
+		version = int(v)
+	}
+
+	for ; version < CurrentVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return Settings{}, fmt.Errorf("no migration from schema version %d", version)
+		}
+		if err := migrate(raw); err != nil {
+			return Settings{}, fmt.Errorf("migrate from version %d: %w", version, err)
+		}
+	}
+	raw["version"] = int64(version)
+
+	// Перекодируем мигрированную карту, чтобы разобрать её в структуру
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return Settings{}, fmt.Errorf("re-encode: %w", err)
+	}
+
+	s := Defaults()
+	if _, err := toml.Decode(buf.String(), &s); err != nil {
+		return Settings{}, fmt.Errorf("decode: %w", err)
+	}
+
+	s.Validate()
+	return s, nil
+}
+
+// Save атомарно записывает настройки: во временный файл рядом с целевым,
+// затем переименование поверх старого файла. Настройки более новой версии
+// не записываются.
+func Save(path string, s Settings) error {
+	s.Validate()
+	if s.Version > CurrentVersion {
+		return fmt.Errorf("%w: %s (version %d)", ErrTooNew, path, s.Version)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(s); err != nil {
+		return fmt.Errorf("settings: encode: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("settings: create %s: %w", dir, err)
+	}
+
+	if err := atomicfile.WriteFile(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("settings: %w", err)
+	}
+	return nil
+}
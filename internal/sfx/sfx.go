@@ -0,0 +1,280 @@
+// Package sfx проигрывает короткие звуковые эффекты. Клипы заранее
+// декодируются в память, а играют через пул плееров с ограничением числа
+// одновременных копий каждой реплики и случайным разбросом высоты и громкости.
+package sfx
+
+import (
+	"aethelgard/internal/mixer"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// bytesPerFrame — 16 бит на канал, два канала
+const bytesPerFrame = 4
+
+// pitchStep — шаг, с которым квантуется высота; варианты клипа с одинаковой
+// высотой пересэмплируются один раз
+const pitchStep = 0.01
+
+// Cue — именованная реплика из файла описаний
+type Cue struct {
+	// Files — клипы реплики; при каждом проигрывании выбирается случайный
+	Files []string `json:"files"`
+	// Bus — шина микшера; по умолчанию sfx
+	Bus mixer.Bus `json:"bus"`
+	// Volume — громкость 0..1; по умолчанию 1
+	Volume float64 `json:"volume"`
+	// VolumeJitter — случайный разброс громкости, доля от Volume
+	VolumeJitter float64 `json:"volume_jitter"`
+	// PitchJitter — случайный разброс высоты: 0.05 — от 0.95 до 1.05
+	PitchJitter float64 `json:"pitch_jitter"`
+	// MaxVoices — сколько копий реплики может звучать одновременно; по умолчанию 1
+	MaxVoices int `json:"max_voices"`
+}
+
+// Decoder декодирует клип из ресурсов в PCM контекста; поток закрывается
+// после того, как клип прочитан в память
+type Decoder func(path string) (io.ReadCloser, error)
+
+// clip — декодированный клип и его пересэмплированные варианты по высоте
+type clip struct {
+	pcm      []byte
+	variants map[int][]byte
+}
+
+// voice — плеер пула и данные, которые он играет
+type voice struct {
+	player  *audio.Player
+	data    []byte
+	started time.Time
+}
+
+type cue struct {
+	Cue
+	clips []*clip
+	pool  []*voice
+}
+
+// Service — набор реплик и их плееров
+type Service struct {
+	ctx    *audio.Context
+	mixer  *mixer.Mixer
+	decode Decoder
+	cues   map[string]*cue
+	rand   *rand.Rand
+}
+
+// New создаёт сервис без реплик
+func New(ctx *audio.Context, m *mixer.Mixer, decode Decoder) *Service {
+	return &Service{
+		ctx:    ctx,
+		mixer:  m,
+		decode: decode,
+		cues:   make(map[string]*cue),
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Load читает файл описаний реплик (JSON: имя → Cue) и декодирует их клипы.
+// Клипы, которые не удалось декодировать, пропускаются с предупреждением.
+func (s *Service) Load(fsys fs.FS, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	var defs map[string]Cue
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return fmt.Errorf("sfx: %s: %w", name, err)
+	}
+
+	for cueName, def := range defs {
+		if def.Bus == "" {
+			def.Bus = mixer.SFX
+		}
+		if def.Volume <= 0 {
+			def.Volume = 1
+		}
+		if def.MaxVoices <= 0 {
+			def.MaxVoices = 1
+		}
+
+		c := &cue{Cue: def}
+		for _, file := range def.Files {
+			pcm, err := s.load(file)
+			if err != nil {
+				log.Printf("Warning: sfx: cue %q: %s: %v", cueName, file, err)
+				continue
+			}
+			c.clips = append(c.clips, &clip{pcm: pcm, variants: make(map[int][]byte)})
+		}
+		if old, ok := s.cues[cueName]; ok {
+			s.closeCue(old)
+		}
+		s.cues[cueName] = c
+	}
+	return nil
+}
+
+func (s *Service) load(file string) ([]byte, error) {
+	r, err := s.decode(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	pcm, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return pcm[:len(pcm)/bytesPerFrame*bytesPerFrame], nil
+}
+
+// Has сообщает, есть ли реплика с хотя бы одним клипом
+func (s *Service) Has(name string) bool {
+	c, ok := s.cues[name]
+	return ok && len(c.clips) > 0
+}
+
+// Play проигрывает реплику. Если все плееры реплики заняты, самая старая
+// копия обрывается; неизвестная реплика молча игнорируется.
+func (s *Service) Play(name string) {
+	c, ok := s.cues[name]
+	if !ok || len(c.clips) == 0 {
+		return
+	}
+
+	cl := c.clips[s.rand.Intn(len(c.clips))]
+	data := cl.variant(s.jitter(c.PitchJitter))
+	gain := math.Max(0, math.Min(1, c.Volume*s.jitter(c.VolumeJitter)))
+
+	v := c.voiceFor(data)
+	if v == nil {
+		player := s.ctx.NewPlayerFromBytes(data)
+		v = &voice{player: player, data: data}
+		c.pool = append(c.pool, v)
+	} else if v.player.IsPlaying() {
+		v.player.Pause()
+	}
+
+	if !sameData(v.data, data) {
+		// Свободный плеер играет другой вариант — заменяем его
+		s.mixer.Detach(v.player)
+		v.player.Close()
+		v.player = s.ctx.NewPlayerFromBytes(data)
+		v.data = data
+	}
+	if err := v.player.Rewind(); err != nil {
+		log.Printf("Warning: sfx: cue %q: %v", name, err)
+		return
+	}
+	s.mixer.Attach(c.Bus, v.player, gain)
+	v.started = time.Now()
+	v.player.Play()
+}
+
+// Close останавливает и закрывает все плееры
+func (s *Service) Close() {
+	for _, c := range s.cues {
+		s.closeCue(c)
+	}
+}
+
+func (s *Service) closeCue(c *cue) {
+	for _, v := range c.pool {
+		s.mixer.Detach(v.player)
+		v.player.Close()
+	}
+	c.pool = nil
+}
+
+// jitter возвращает случайный множитель 1±amount
+func (s *Service) jitter(amount float64) float64 {
+	if amount <= 0 {
+		return 1
+	}
+	return 1 + (s.rand.Float64()*2-1)*amount
+}
+
+// voiceFor выбирает плеер из пула: свободный с теми же данными, любой
+// свободный, новый, если пул не заполнен (nil), или самый старый играющий
+func (c *cue) voiceFor(data []byte) *voice {
+	var idle, oldest *voice
+	for _, v := range c.pool {
+		if v.player.IsPlaying() {
+			if oldest == nil || v.started.Before(oldest.started) {
+				oldest = v
+			}
+			continue
+		}
+		if sameData(v.data, data) {
+			return v
+		}
+		if idle == nil {
+			idle = v
+		}
+	}
+	if len(c.pool) < c.MaxVoices {
+		return nil
+	}
+	if idle != nil {
+		return idle
+	}
+	return oldest
+}
+
+// variant возвращает клип, пересэмплированный к высоте pitch
+func (c *clip) variant(pitch float64) []byte {
+	pitch = math.Max(pitch, 0.25)
+	step := int(math.Round((pitch - 1) / pitchStep))
+	if step == 0 {
+		return c.pcm
+	}
+	if data, ok := c.variants[step]; ok {
+		return data
+	}
+	data := resample(c.pcm, 1+float64(step)*pitchStep)
+	c.variants[step] = data
+	return data
+}
+
+// resample меняет высоту и длительность клипа в pitch раз линейной интерполяцией
+func resample(pcm []byte, pitch float64) []byte {
+	frames := len(pcm) / bytesPerFrame
+	if frames < 2 {
+		return pcm
+	}
+	outFrames := int(float64(frames-1)/pitch) + 1
+	out := make([]byte, outFrames*bytesPerFrame)
+
+	sample := func(frame, ch int) float64 {
+		i := frame*bytesPerFrame + ch*2
+		return float64(int16(uint16(pcm[i]) | uint16(pcm[i+1])<<8))
+	}
+	for i := 0; i < outFrames; i++ {
+		pos := float64(i) * pitch
+		f := int(pos)
+		if f >= frames-1 {
+			f = frames - 2
+		}
+		t := pos - float64(f)
+		for ch := 0; ch < 2; ch++ {
+			v := int16(math.Round(sample(f, ch)*(1-t) + sample(f+1, ch)*t))
+			o := i*bytesPerFrame + ch*2
+			out[o] = byte(v)
+			out[o+1] = byte(uint16(v) >> 8)
+		}
+	}
+	return out
+}
+
+// sameData сравнивает буферы по адресу: варианты клипа не копируются
+func sameData(a, b []byte) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
@@ -0,0 +1,43 @@
+package sprite
+
+import (
+	"image"
+	"sort"
+)
+
+// Pack раскладывает прямоугольники по полкам: от высоких к низким, слева
+// направо, пока полка не упрётся в maxW, затем новая полка ниже. Между
+// прямоугольниками и по краям остаётся pad пикселей. Возвращает левые
+// верхние углы в порядке sizes и размер атласа. Прямоугольник шире maxW
+// получает полку на себя.
+func Pack(sizes []image.Point, maxW, pad int) ([]image.Point, image.Point) {
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return sizes[order[i]].Y > sizes[order[j]].Y
+	})
+
+	pos := make([]image.Point, len(sizes))
+	var size image.Point
+	x, y, shelf := pad, pad, 0
+	for _, i := range order {
+		s := sizes[i]
+		if x > pad && x+s.X+pad > maxW {
+			x, y, shelf = pad, y+shelf+pad, 0
+		}
+		pos[i] = image.Pt(x, y)
+		x += s.X + pad
+		if s.Y > shelf {
+			shelf = s.Y
+		}
+		if x > size.X {
+			size.X = x
+		}
+		if y+shelf+pad > size.Y {
+			size.Y = y + shelf + pad
+		}
+	}
+	return pos, size
+}
@@ -0,0 +1,207 @@
+// Package sprite — спрайты персонажей: листы кадров, которые при загрузке
+// упаковываются в один атлас, и отрисовка кадра анимации. Описание
+// спрайта — JSON рядом с листами: листы с размером и опорной точкой
+// кадра, а также анимации и машина состояний, которые читает пакет anim.
+package sprite
+
+import (
+	"aethelgard/internal/anim"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/png"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MaxAtlasWidth — ширина, на которой упаковщик начинает новую полку
+const MaxAtlasWidth = 1024
+
+// padding — прозрачный зазор между кадрами в атласе, чтобы соседний кадр
+// не просвечивал при дробном масштабе камеры
+const padding = 1
+
+// Frame — кадр в атласе и его опорная точка (ноги персонажа) от левого
+// верхнего угла кадра
+type Frame struct {
+	Image          *ebiten.Image
+	Rect           image.Rectangle
+	PivotX, PivotY float64
+}
+
+// Sheet — кадры одного спрайта в порядке листов и анимации над ними
+type Sheet struct {
+	Name   string
+	Frames []Frame
+	Anim   *anim.Set
+}
+
+// Atlas — атлас со всеми кадрами загруженных спрайтов
+type Atlas struct {
+	Image  *ebiten.Image
+	Sheets map[string]*Sheet
+}
+
+// rawSheet — лист в описании: картинка относительно описания, сетка
+// кадров и опорная точка кадра. Count ограничивает число кадров, если
+// последний ряд заполнен не до конца.
+type rawSheet struct {
+	Image       string     `json:"image"`
+	FrameWidth  int        `json:"frame_width"`
+	FrameHeight int        `json:"frame_height"`
+	Pivot       [2]float64 `json:"pivot"`
+	Count       int        `json:"count"`
+}
+
+type rawSprite struct {
+	Sheets []rawSheet `json:"sheets"`
+}
+
+// cut — кадр листа до упаковки
+type cut struct {
+	sheet *Sheet
+	index int
+	src   image.Image
+	rect  image.Rectangle
+	pivot [2]float64
+}
+
+// Load читает описания спрайтов и упаковывает кадры всех листов в один
+// атлас. Имя спрайта — имя файла описания без расширения.
+func Load(fsys fs.FS, names ...string) (*Atlas, error) {
+	a := &Atlas{Sheets: make(map[string]*Sheet, len(names))}
+	var cuts []cut
+	for _, name := range names {
+		s, c, err := loadSheet(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := a.Sheets[s.Name]; ok {
+			return nil, fmt.Errorf("sprite: %s: duplicate sprite %q", name, s.Name)
+		}
+		a.Sheets[s.Name] = s
+		cuts = append(cuts, c...)
+	}
+
+	sizes := make([]image.Point, len(cuts))
+	for i, c := range cuts {
+		sizes[i] = c.rect.Size()
+	}
+	pos, size := Pack(sizes, MaxAtlasWidth, padding)
+	if size.X == 0 || size.Y == 0 {
+		return nil, fmt.Errorf("sprite: no frames to pack")
+	}
+
+	dst := image.NewRGBA(image.Rectangle{Max: size})
+	for i, c := range cuts {
+		r := image.Rectangle{Min: pos[i], Max: pos[i].Add(c.rect.Size())}
+		draw.Draw(dst, r, c.src, c.rect.Min, draw.Src)
+	}
+	a.Image = ebiten.NewImageFromImage(dst)
+	for i, c := range cuts {
+		r := image.Rectangle{Min: pos[i], Max: pos[i].Add(c.rect.Size())}
+		c.sheet.Frames[c.index] = Frame{
+			Image:  a.Image.SubImage(r).(*ebiten.Image),
+			Rect:   r,
+			PivotX: c.pivot[0],
+			PivotY: c.pivot[1],
+		}
+	}
+	return a, nil
+}
+
+// loadSheet читает описание спрайта, режет его листы на кадры и разбирает
+// анимации
+func loadSheet(fsys fs.FS, name string) (*Sheet, []cut, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sprite: %w", err)
+	}
+	var raw rawSprite
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("sprite: %s: %w", name, err)
+	}
+
+	s := &Sheet{Name: strings.TrimSuffix(path.Base(name), path.Ext(name))}
+	var cuts []cut
+	for _, rs := range raw.Sheets {
+		if rs.FrameWidth <= 0 || rs.FrameHeight <= 0 {
+			return nil, nil, fmt.Errorf("sprite: %s: sheet %q: bad frame size %dx%d", name, rs.Image, rs.FrameWidth, rs.FrameHeight)
+		}
+		file := path.Join(path.Dir(name), rs.Image)
+		img, err := decode(fsys, file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("sprite: %s: %w", name, err)
+		}
+
+		b := img.Bounds()
+		cols, rows := b.Dx()/rs.FrameWidth, b.Dy()/rs.FrameHeight
+		count := cols * rows
+		if rs.Count > 0 && rs.Count < count {
+			count = rs.Count
+		}
+		for i := 0; i < count; i++ {
+			min := b.Min.Add(image.Pt(i%cols*rs.FrameWidth, i/cols*rs.FrameHeight))
+			cuts = append(cuts, cut{
+				sheet: s,
+				index: len(cuts),
+				src:   img,
+				rect:  image.Rectangle{Min: min, Max: min.Add(image.Pt(rs.FrameWidth, rs.FrameHeight))},
+				pivot: rs.Pivot,
+			})
+		}
+	}
+	s.Frames = make([]Frame, len(cuts))
+
+	s.Anim, err = anim.Parse(s.Name, data, len(cuts))
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, cuts, nil
+}
+
+func decode(fsys fs.FS, name string) (image.Image, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return img, nil
+}
+
+// Library возвращает анимации всех спрайтов атласа для мира сущностей
+func (a *Atlas) Library() anim.Library {
+	lib := make(anim.Library, len(a.Sheets))
+	for name, s := range a.Sheets {
+		lib[name] = s.Anim
+	}
+	return lib
+}
+
+// Dispose освобождает картинку атласа
+func (a *Atlas) Dispose() {
+	if a.Image != nil {
+		a.Image.Dispose()
+		a.Image = nil
+	}
+}
+
+// Draw рисует кадр так, что его опорная точка попадает в начало
+// координат geoM; flipX отражает кадр вокруг опорной точки
+func (f *Frame) Draw(dst *ebiten.Image, geoM ebiten.GeoM, flipX bool) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-f.PivotX, -f.PivotY)
+	if flipX {
+		op.GeoM.Scale(-1, 1)
+	}
+	op.GeoM.Concat(geoM)
+	dst.DrawImage(f.Image, op)
+}
@@ -0,0 +1,197 @@
+// Package subtitles читает субтитры в форматах SRT и WebVTT и выдаёт
+// реплики, которые должны быть на экране в заданный момент ролика.
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cue — реплика с временем появления и исчезновения
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// Track — реплики ролика, отсортированные по времени появления
+type Track struct {
+	Cues []Cue
+}
+
+// Parse читает SRT или WebVTT; формат определяется по заголовку WEBVTT
+func Parse(r io.Reader) (*Track, error) {
+	blocks, err := readBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+
+	vtt := len(blocks) > 0 && strings.HasPrefix(blocks[0][0], "WEBVTT")
+	if vtt {
+		blocks = blocks[1:]
+	}
+
+	t := &Track{}
+	for _, block := range blocks {
+		if vtt && isVTTMetadata(block[0]) {
+			continue
+		}
+
+		// Первая строка блока — номер (SRT) или идентификатор (WebVTT), если это не время
+		timing := 0
+		if !strings.Contains(block[0], "-->") {
+			timing = 1
+		}
+		if timing >= len(block) {
+			continue
+		}
+
+		start, end, err := parseTiming(block[timing])
+		if err != nil {
+			return nil, fmt.Errorf("subtitles: %q: %w", block[timing], err)
+		}
+
+		lines := make([]string, 0, len(block)-timing-1)
+		for _, line := range block[timing+1:] {
+			lines = append(lines, stripTags(line))
+		}
+		t.Cues = append(t.Cues, Cue{Start: start, End: end, Text: strings.Join(lines, "\n")})
+	}
+
+	sort.SliceStable(t.Cues, func(i, j int) bool {
+		return t.Cues[i].Start < t.Cues[j].Start
+	})
+	return t, nil
+}
+
+// At возвращает тексты реплик, активных в момент pos; реплики могут перекрываться
+func (t *Track) At(pos time.Duration) []string {
+	if t == nil {
+		return nil
+	}
+
+	var out []string
+	for _, c := range t.Cues {
+		if c.Start > pos {
+			break
+		}
+		if pos < c.End && c.Text != "" {
+			out = append(out, c.Text)
+		}
+	}
+	return out
+}
+
+// readBlocks делит файл на блоки строк, разделённые пустыми строками
+func readBlocks(r io.Reader) ([][]string, error) {
+	var blocks [][]string
+	var cur []string
+
+	sc := bufio.NewScanner(r)
+	first := true
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+
+		if strings.TrimSpace(line) == "" {
+			if len(cur) > 0 {
+				blocks = append(blocks, cur)
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("subtitles: read: %w", err)
+	}
+	if len(cur) > 0 {
+		blocks = append(blocks, cur)
+	}
+	return blocks, nil
+}
+
+// isVTTMetadata отличает служебные блоки WebVTT от реплик
+func isVTTMetadata(first string) bool {
+	for _, prefix := range []string{"NOTE", "STYLE", "REGION"} {
+		if first == prefix || strings.HasPrefix(first, prefix+" ") || strings.HasPrefix(first, prefix+"\t") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTiming разбирает строку "00:00:01,000 --> 00:00:04,000"; после времени
+// окончания в WebVTT могут идти настройки положения, они игнорируются
+func parseTiming(line string) (time.Duration, time.Duration, error) {
+	parts := strings.SplitN(line, "-->", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("missing -->")
+	}
+
+	start, err := parseTimestamp(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+	endFields := strings.Fields(parts[1])
+	if len(endFields) == 0 {
+		return 0, 0, fmt.Errorf("missing end time")
+	}
+	end, err := parseTimestamp(endFields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseTimestamp разбирает [чч:]мм:сс,ммм (SRT) и [чч:]мм:сс.ммм (WebVTT)
+func parseTimestamp(s string) (time.Duration, error) {
+	s = strings.Replace(s, ",", ".", 1)
+
+	var frac time.Duration
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		ms := s[dot+1:]
+		for len(ms) < 3 {
+			ms += "0"
+		}
+		n, err := strconv.Atoi(ms[:3])
+		if err != nil {
+			return 0, fmt.Errorf("bad milliseconds in %q", s)
+		}
+		frac = time.Duration(n) * time.Millisecond
+		s = s[:dot]
+	}
+
+	fields := strings.Split(s, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, fmt.Errorf("bad timestamp %q", s)
+	}
+
+	var total time.Duration
+	for _, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return 0, fmt.Errorf("bad timestamp %q", s)
+		}
+		total = total*60 + time.Duration(n)*time.Second
+	}
+	return total + frac, nil
+}
+
+var tagPattern = regexp.MustCompile(`</?[^>]*>`)
+
+// stripTags убирает разметку <i>, <b>, <font ...>, <v Имя> и прочие теги
+func stripTags(s string) string {
+	s = tagPattern.ReplaceAllString(s, "")
+	s = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&nbsp;", " ").Replace(s)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,303 @@
+// Package tilemap — карта мира из тайлов: несколько слоёв (земля, декор,
+// столкновения, верхний слой), разбитых на квадратные чанки, наборы тайлов
+// в атласах и объекты карты. Карты загружаются из формата редактора Tiled
+// (TMJ или TMX), а рисуются пакетами: тайлы всех видимых чанков слоя,
+// лежащие в одном атласе, выводятся одним вызовом DrawTriangles.
+//
+// Поддерживаемое подмножество Tiled:
+//
+//   - ортогональные карты, конечные и бесконечные (с чанками);
+//   - данные слоёв в CSV или base64, без сжатия, gzip или zlib;
+//   - наборы тайлов с одним изображением-атласом, встроенные или внешние
+//     (.tsj, .tsx), с полями margin и spacing;
+//   - отражение тайлов по горизонтали, вертикали и диагонали;
+//   - группы слоёв (разворачиваются в плоский список) и слои объектов;
+//   - строковые, числовые и логические свойства карты, слоёв и объектов.
+//
+// Назначение тайлового слоя задаётся свойством kind (ground, decoration,
+// collision, overlay), а без него — именем слоя. Слои без назначения
+// считаются декором. Любой непустой тайл слоя collision непроходим; такие
+// слои не рисуются. Слои overlay рисуются поверх персонажей.
+package tilemap
+
+import (
+	"image"
+	"math"
+	"strings"
+)
+
+// ChunkSize — сторона чанка в тайлах
+const ChunkSize = 16
+
+// Биты отражения в старших разрядах номера тайла (GID) Tiled
+const (
+	FlipHorizontal uint32 = 0x80000000
+	FlipVertical   uint32 = 0x40000000
+	FlipDiagonal   uint32 = 0x20000000
+
+	flipMask = FlipHorizontal | FlipVertical | FlipDiagonal | 0x10000000
+)
+
+// GID — номер тайла карты: индекс в наборах тайлов и биты отражения;
+// 0 — пустая клетка
+type GID uint32
+
+// ID возвращает номер тайла без битов отражения
+func (g GID) ID() uint32 {
+	return uint32(g) &^ flipMask
+}
+
+// Flags возвращает биты отражения
+func (g GID) Flags() uint32 {
+	return uint32(g) & (FlipHorizontal | FlipVertical | FlipDiagonal)
+}
+
+// Kind — назначение тайлового слоя
+type Kind int
+
+const (
+	Decoration Kind = iota
+	Ground
+	Collision
+	Overlay
+)
+
+var kindNames = map[string]Kind{
+	"ground":     Ground,
+	"decoration": Decoration,
+	"collision":  Collision,
+	"overlay":    Overlay,
+}
+
+func (k Kind) String() string {
+	for name, kind := range kindNames {
+		if kind == k {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// kindOf определяет назначение слоя по свойству kind или по имени
+func kindOf(name string, props Properties) Kind {
+	if k, ok := kindNames[strings.ToLower(props[PropKind])]; ok {
+		return k
+	}
+	if k, ok := kindNames[strings.ToLower(name)]; ok {
+		return k
+	}
+	return Decoration
+}
+
+// PropKind — свойство слоя с его назначением
+const PropKind = "kind"
+
+// Properties — пользовательские свойства Tiled; значения любых типов
+// хранятся строками
+type Properties map[string]string
+
+// ChunkCoord — координаты чанка: тайл (x, y) лежит в чанке
+// (floor(x/ChunkSize), floor(y/ChunkSize))
+type ChunkCoord struct {
+	X, Y int
+}
+
+// ChunkOf возвращает чанк, в котором лежит тайл
+func ChunkOf(tx, ty int) ChunkCoord {
+	return ChunkCoord{floorDiv(tx, ChunkSize), floorDiv(ty, ChunkSize)}
+}
+
+// Tiles возвращает прямоугольник тайлов чанка
+func (c ChunkCoord) Tiles() image.Rectangle {
+	return image.Rect(c.X*ChunkSize, c.Y*ChunkSize, (c.X+1)*ChunkSize, (c.Y+1)*ChunkSize)
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// chunk — тайлы одного чанка слоя построчно
+type chunk [ChunkSize * ChunkSize]GID
+
+// Layer — тайловый слой
+type Layer struct {
+	Name       string
+	Kind       Kind
+	Visible    bool
+	Opacity    float64
+	Properties Properties
+
+	chunks map[ChunkCoord]*chunk
+}
+
+func newLayer(name string, props Properties) *Layer {
+	return &Layer{
+		Name:       name,
+		Kind:       kindOf(name, props),
+		Visible:    true,
+		Opacity:    1,
+		Properties: props,
+		chunks:     make(map[ChunkCoord]*chunk),
+	}
+}
+
+// At возвращает тайл слоя в клетке; за пределами данных — 0
+func (l *Layer) At(tx, ty int) GID {
+	c, ok := l.chunks[ChunkOf(tx, ty)]
+	if !ok {
+		return 0
+	}
+	return c[(ty-floorDiv(ty, ChunkSize)*ChunkSize)*ChunkSize+tx-floorDiv(tx, ChunkSize)*ChunkSize]
+}
+
+// set записывает тайл; пустые клетки не создают чанков
+func (l *Layer) set(tx, ty int, gid GID) {
+	key := ChunkOf(tx, ty)
+	c, ok := l.chunks[key]
+	if !ok {
+		if gid == 0 {
+			return
+		}
+		c = &chunk{}
+		l.chunks[key] = c
+	}
+	c[(ty-key.Y*ChunkSize)*ChunkSize+tx-key.X*ChunkSize] = gid
+}
+
+// Tileset — набор тайлов из одного изображения-атласа
+type Tileset struct {
+	Name     string
+	FirstGID uint32
+
+	TileWidth, TileHeight int
+	TileCount, Columns    int
+	Margin, Spacing       int
+
+	// Image — путь к атласу в файловой системе ресурсов
+	Image                   string
+	ImageWidth, ImageHeight int
+}
+
+// Contains сообщает, что тайл относится к набору
+func (ts *Tileset) Contains(id uint32) bool {
+	return id >= ts.FirstGID && id < ts.FirstGID+uint32(ts.TileCount)
+}
+
+// Rect возвращает прямоугольник тайла в атласе
+func (ts *Tileset) Rect(id uint32) image.Rectangle {
+	local := int(id - ts.FirstGID)
+	col, row := local%ts.Columns, local/ts.Columns
+	x := ts.Margin + col*(ts.TileWidth+ts.Spacing)
+	y := ts.Margin + row*(ts.TileHeight+ts.Spacing)
+	return image.Rect(x, y, x+ts.TileWidth, y+ts.TileHeight)
+}
+
+// Object — объект слоя объектов: точка появления, дверь, табличка
+type Object struct {
+	ID         int
+	Name       string
+	Type       string
+	Layer      string
+	X, Y       float64
+	Width      float64
+	Height     float64
+	Properties Properties
+}
+
+// Bounds возвращает прямоугольник объекта в пикселях карты
+func (o Object) Bounds() image.Rectangle {
+	return image.Rect(int(o.X), int(o.Y), int(o.X+o.Width), int(o.Y+o.Height))
+}
+
+// Map — карта мира
+type Map struct {
+	TileWidth, TileHeight int
+
+	// Bounds — прямоугольник карты в тайлах; у бесконечных карт он охватывает
+	// все чанки и может начинаться с отрицательных координат
+	Bounds image.Rectangle
+
+	Tilesets   []*Tileset
+	Layers     []*Layer
+	Objects    []Object
+	Properties Properties
+}
+
+// PixelBounds возвращает прямоугольник карты в пикселях
+func (m *Map) PixelBounds() image.Rectangle {
+	return image.Rect(
+		m.Bounds.Min.X*m.TileWidth, m.Bounds.Min.Y*m.TileHeight,
+		m.Bounds.Max.X*m.TileWidth, m.Bounds.Max.Y*m.TileHeight,
+	)
+}
+
+// TileAt переводит точку в пикселях карты в координаты тайла
+func (m *Map) TileAt(x, y float64) (int, int) {
+	return floorDiv(int(math.Floor(x)), m.TileWidth), floorDiv(int(math.Floor(y)), m.TileHeight)
+}
+
+// Tileset возвращает набор, в котором лежит тайл
+func (m *Map) Tileset(id uint32) (*Tileset, int) {
+	for i := len(m.Tilesets) - 1; i >= 0; i-- {
+		if ts := m.Tilesets[i]; id >= ts.FirstGID {
+			if ts.Contains(id) {
+				return ts, i
+			}
+			break
+		}
+	}
+	return nil, -1
+}
+
+// Layer возвращает слой по имени
+func (m *Map) Layer(name string) *Layer {
+	for _, l := range m.Layers {
+		if l.Name == name {
+			return l
+		}
+	}
+	return nil
+}
+
+// Solid сообщает, что клетка непроходима: в ней есть тайл слоя столкновений.
+// Клетки за пределами карты непроходимы.
+func (m *Map) Solid(tx, ty int) bool {
+	if !(image.Point{tx, ty}).In(m.Bounds) {
+		return true
+	}
+	for _, l := range m.Layers {
+		if l.Kind == Collision && l.At(tx, ty) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Object возвращает первый объект с именем
+func (m *Map) Object(name string) (Object, bool) {
+	for _, o := range m.Objects {
+		if o.Name == name {
+			return o, true
+		}
+	}
+	return Object{}, false
+}
+
+// Chunks возвращает чанки, в которых есть тайлы хотя бы одного слоя
+func (m *Map) Chunks() []ChunkCoord {
+	seen := make(map[ChunkCoord]bool)
+	var out []ChunkCoord
+	for _, l := range m.Layers {
+		for c := range l.chunks {
+			if !seen[c] {
+				seen[c] = true
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
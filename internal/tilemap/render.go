@@ -0,0 +1,280 @@
+package tilemap
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// DefaultRadius — сколько чанков вокруг точки интереса держится готовыми
+const DefaultRadius = 2
+
+// maxBatchVertices — предел вершин одного вызова DrawTriangles: номера вершин
+// в индексах — uint16. Индексов в вызове тоже не больше
+// ebiten.MaxIndicesCount, и этот предел наступает раньше: на тайл уходит
+// четыре вершины, но шесть индексов.
+const maxBatchVertices = 1 << 16
+
+// batch — вершины тайлов чанка одного слоя из одного атласа в координатах карты
+type batch struct {
+	layer    int
+	tileset  int
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// chunkBatches — подготовленные пакеты чанка
+type chunkBatches struct {
+	bounds  image.Rectangle // в пикселях карты
+	batches []batch
+}
+
+// Renderer рисует карту. Вершины тайлов строятся для чанков вокруг точки
+// интереса (Update) и выбрасываются, когда она уходит дальше; при отрисовке
+// пакеты всех видимых чанков слоя склеиваются в один вызов на атлас.
+type Renderer struct {
+	m       *Map
+	atlases []*ebiten.Image
+
+	// Radius — сколько чанков вокруг точки интереса держится готовыми;
+	// чанки дальше Radius+1 выгружаются
+	Radius int
+
+	loaded map[ChunkCoord]*chunkBatches
+
+	// Буферы склейки, переиспользуются между кадрами
+	vertices []ebiten.Vertex
+	indices  []uint16
+
+	stats RenderStats
+}
+
+// RenderStats — сведения о кадре для отладочного оверлея: всё, кроме
+// LoadedChunks, накапливается вызовами Draw с последнего ResetStats
+type RenderStats struct {
+	LoadedChunks int
+	DrawnChunks  int
+	DrawCalls    int
+	Tiles        int
+}
+
+// NewRenderer загружает атласы наборов тайлов карты
+func NewRenderer(fsys fs.FS, m *Map) (*Renderer, error) {
+	r := &Renderer{
+		m:      m,
+		Radius: DefaultRadius,
+		loaded: make(map[ChunkCoord]*chunkBatches),
+	}
+	for _, ts := range m.Tilesets {
+		img, _, err := ebitenutil.NewImageFromFileSystem(fsys, ts.Image)
+		if err != nil {
+			r.Dispose()
+			return nil, fmt.Errorf("tilemap: tileset %q: %w", ts.Name, err)
+		}
+		r.atlases = append(r.atlases, img)
+	}
+	return r, nil
+}
+
+// Dispose освобождает атласы
+func (r *Renderer) Dispose() {
+	for _, img := range r.atlases {
+		img.Dispose()
+	}
+	r.atlases = nil
+	r.loaded = make(map[ChunkCoord]*chunkBatches)
+}
+
+// Stats возвращает сведения о кадре
+func (r *Renderer) Stats() RenderStats {
+	return r.stats
+}
+
+// ResetStats обнуляет счётчики отрисовки; вызывается в начале кадра, до
+// первой камеры
+func (r *Renderer) ResetStats() {
+	r.stats.DrawCalls, r.stats.DrawnChunks, r.stats.Tiles = 0, 0, 0
+}
+
+// Update подгружает чанки в радиусе Radius вокруг точки интереса (в пикселях
+// карты) и выгружает те, что дальше Radius+1: запас в один чанк не даёт
+// чанку на границе загружаться и выгружаться каждый кадр
+func (r *Renderer) Update(focusX, focusY float64) {
+	center := ChunkOf(r.m.TileAt(focusX, focusY))
+
+	for c := range r.loaded {
+		if abs(c.X-center.X) > r.Radius+1 || abs(c.Y-center.Y) > r.Radius+1 {
+			delete(r.loaded, c)
+		}
+	}
+	for y := center.Y - r.Radius; y <= center.Y+r.Radius; y++ {
+		for x := center.X - r.Radius; x <= center.X+r.Radius; x++ {
+			c := ChunkCoord{x, y}
+			if _, ok := r.loaded[c]; !ok {
+				r.loaded[c] = r.build(c)
+			}
+		}
+	}
+	r.stats.LoadedChunks = len(r.loaded)
+}
+
+// build строит вершины тайлов чанка по слоям и атласам
+func (r *Renderer) build(c ChunkCoord) *chunkBatches {
+	tw, th := r.m.TileWidth, r.m.TileHeight
+	tiles := c.Tiles()
+	cb := &chunkBatches{
+		bounds: image.Rect(tiles.Min.X*tw, tiles.Min.Y*th, tiles.Max.X*tw, tiles.Max.Y*th),
+	}
+
+	for li, l := range r.m.Layers {
+		if l.Kind == Collision || !l.Visible {
+			continue
+		}
+		data, ok := l.chunks[c]
+		if !ok {
+			continue
+		}
+
+		// Пакет на каждый атлас, который встречается в чанке слоя
+		byTileset := make(map[int]int)
+		for i, gid := range data {
+			if gid == 0 {
+				continue
+			}
+			ts, tsi := r.m.Tileset(gid.ID())
+			if ts == nil {
+				continue
+			}
+			bi, ok := byTileset[tsi]
+			if !ok {
+				bi = len(cb.batches)
+				byTileset[tsi] = bi
+				cb.batches = append(cb.batches, batch{layer: li, tileset: tsi})
+			}
+
+			// Тайл выше клетки выравнивается по её нижнему левому углу, как в Tiled
+			x := float32((tiles.Min.X + i%ChunkSize) * tw)
+			y := float32((tiles.Min.Y+i/ChunkSize+1)*th - ts.TileHeight)
+			b := &cb.batches[bi]
+			b.vertices, b.indices = appendTile(b.vertices, b.indices, x, y, ts, gid, float32(l.Opacity))
+		}
+	}
+	return cb
+}
+
+// appendTile добавляет четыре вершины и два треугольника тайла
+func appendTile(vs []ebiten.Vertex, is []uint16, x, y float32, ts *Tileset, gid GID, alpha float32) ([]ebiten.Vertex, []uint16) {
+	src := ts.Rect(gid.ID())
+	w, h := float32(ts.TileWidth), float32(ts.TileHeight)
+
+	// Углы атласа в порядке: левый верхний, правый верхний, левый нижний, правый нижний
+	u0, v0, u1, v1 := float32(src.Min.X), float32(src.Min.Y), float32(src.Max.X), float32(src.Max.Y)
+	uv := [4][2]float32{{u0, v0}, {u1, v0}, {u0, v1}, {u1, v1}}
+	flags := gid.Flags()
+	if flags&FlipDiagonal != 0 {
+		uv[1], uv[2] = uv[2], uv[1]
+	}
+	if flags&FlipHorizontal != 0 {
+		uv[0], uv[1], uv[2], uv[3] = uv[1], uv[0], uv[3], uv[2]
+	}
+	if flags&FlipVertical != 0 {
+		uv[0], uv[1], uv[2], uv[3] = uv[2], uv[3], uv[0], uv[1]
+	}
+
+	base := uint16(len(vs))
+	pos := [4][2]float32{{x, y}, {x + w, y}, {x, y + h}, {x + w, y + h}}
+	for i := range pos {
+		vs = append(vs, ebiten.Vertex{
+			DstX: pos[i][0], DstY: pos[i][1],
+			SrcX: uv[i][0], SrcY: uv[i][1],
+			ColorR: 1, ColorG: 1, ColorB: 1, ColorA: alpha,
+		})
+	}
+	is = append(is, base, base+1, base+2, base+1, base+3, base+2)
+	return vs, is
+}
+
+// Draw рисует слои указанных назначений в порядке карты. geoM переводит
+// координаты карты в координаты экрана; view — видимая часть карты
+// в пикселях, чанки вне её пропускаются. Рисуются только загруженные чанки.
+func (r *Renderer) Draw(screen *ebiten.Image, geoM ebiten.GeoM, view image.Rectangle, kinds ...Kind) {
+	r.batch(geoM, view, kinds, func(tileset int) {
+		if tileset >= len(r.atlases) {
+			return
+		}
+		screen.DrawTriangles(r.vertices, r.indices, r.atlases[tileset], &ebiten.DrawTrianglesOptions{})
+		r.stats.DrawCalls++
+	})
+}
+
+// batch склеивает пакеты видимых чанков в буфер и отдаёт его emit каждый
+// раз, когда следующий пакет не влезает в один вызов DrawTriangles, и в
+// конце каждой пары слоя и атласа
+func (r *Renderer) batch(geoM ebiten.GeoM, view image.Rectangle, kinds []Kind, emit func(tileset int)) {
+	var visible []*chunkBatches
+	for _, cb := range r.loaded {
+		if cb.bounds.Overlaps(view) && len(cb.batches) > 0 {
+			visible = append(visible, cb)
+		}
+	}
+	r.stats.DrawnChunks += len(visible)
+
+	flush := func(tileset int) {
+		if len(r.indices) > 0 {
+			emit(tileset)
+		}
+		r.vertices, r.indices = r.vertices[:0], r.indices[:0]
+	}
+	for li, l := range r.m.Layers {
+		if !hasKind(kinds, l.Kind) {
+			continue
+		}
+		for tsi := range r.m.Tilesets {
+			for _, cb := range visible {
+				for i := range cb.batches {
+					b := &cb.batches[i]
+					if b.layer != li || b.tileset != tsi {
+						continue
+					}
+					if len(r.vertices)+len(b.vertices) > maxBatchVertices ||
+						len(r.indices)+len(b.indices) > ebiten.MaxIndicesCount {
+						flush(tsi)
+					}
+					r.appendBatch(b, geoM)
+				}
+			}
+			flush(tsi)
+		}
+	}
+}
+
+// appendBatch переносит пакет чанка в буфер склейки, переводя вершины в экран
+func (r *Renderer) appendBatch(b *batch, geoM ebiten.GeoM) {
+	base := uint16(len(r.vertices))
+	for _, v := range b.vertices {
+		x, y := geoM.Apply(float64(v.DstX), float64(v.DstY))
+		v.DstX, v.DstY = float32(x), float32(y)
+		r.vertices = append(r.vertices, v)
+	}
+	for _, i := range b.indices {
+		r.indices = append(r.indices, base+i)
+	}
+	r.stats.Tiles += len(b.vertices) / 4
+}
+
+func hasKind(kinds []Kind, k Kind) bool {
+	for _, kind := range kinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(v int) int {
+	return int(math.Abs(float64(v)))
+}
@@ -0,0 +1,58 @@
+package tilemap
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TestBatchLimits — большой слой делится на вызовы, каждый из которых
+// укладывается в пределы DrawTriangles, и ни один тайл не теряется
+func TestBatchLimits(t *testing.T) {
+	const size = 200
+	ground := newLayer("ground", nil)
+	ground.Kind = Ground
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			ground.set(x, y, 1)
+		}
+	}
+	m := &Map{
+		TileWidth: 16, TileHeight: 16,
+		Bounds: image.Rect(0, 0, size, size),
+		Tilesets: []*Tileset{{
+			Name: "ground", FirstGID: 1,
+			TileWidth: 16, TileHeight: 16,
+			TileCount: 1, Columns: 1,
+		}},
+		Layers: []*Layer{ground},
+	}
+	r := &Renderer{m: m, Radius: size / ChunkSize, loaded: make(map[ChunkCoord]*chunkBatches)}
+	r.Update(size*16/2, size*16/2)
+
+	calls, tiles := 0, 0
+	r.batch(ebiten.GeoM{}, m.PixelBounds(), []Kind{Ground}, func(tileset int) {
+		calls++
+		if tileset != 0 {
+			t.Errorf("call %d: tileset %d, want 0", calls, tileset)
+		}
+		if n := len(r.indices); n > ebiten.MaxIndicesCount {
+			t.Errorf("call %d: %d indices, limit %d", calls, n, ebiten.MaxIndicesCount)
+		}
+		if n := len(r.vertices); n > maxBatchVertices {
+			t.Errorf("call %d: %d vertices, limit %d", calls, n, maxBatchVertices)
+		}
+		if len(r.indices) != len(r.vertices)/4*6 {
+			t.Errorf("call %d: %d indices for %d vertices", calls, len(r.indices), len(r.vertices))
+		}
+		tiles += len(r.vertices) / 4
+	})
+
+	if tiles != size*size {
+		t.Errorf("drew %d tiles, want %d", tiles, size*size)
+	}
+	if want := (size*size*6 + ebiten.MaxIndicesCount - 1) / ebiten.MaxIndicesCount; calls < want {
+		t.Errorf("%d calls, want at least %d", calls, want)
+	}
+}
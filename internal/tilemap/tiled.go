@@ -0,0 +1,680 @@
+package tilemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported — в файле есть возможность Tiled, которую пакет не понимает
+var ErrUnsupported = errors.New("tilemap: unsupported")
+
+// Load читает карту Tiled: .tmj и .json — JSON, .tmx — XML. Внешние наборы
+// тайлов и их атласы ищутся относительно файла, который на них ссылается.
+func Load(fsys fs.FS, name string) (*Map, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw rawMap
+	switch strings.ToLower(path.Ext(name)) {
+	case ".tmj", ".json":
+		err = raw.fromJSON(data)
+	case ".tmx":
+		err = raw.fromXML(data)
+	default:
+		err = fmt.Errorf("%w: map format %q", ErrUnsupported, path.Ext(name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tilemap: %s: %w", name, err)
+	}
+
+	m, err := raw.build(fsys, path.Dir(name))
+	if err != nil {
+		return nil, fmt.Errorf("tilemap: %s: %w", name, err)
+	}
+	return m, nil
+}
+
+// rawMap, rawLayer, rawTileset — карта в том виде, в каком она записана
+// в файле; JSON и XML приводятся к ним, а карта строится из них одинаково
+type rawMap struct {
+	Orientation           string
+	Infinite              bool
+	Width, Height         int
+	TileWidth, TileHeight int
+	Tilesets              []rawTileset
+	Layers                []rawLayer
+	Properties            Properties
+}
+
+type rawLayer struct {
+	Type       string // tilelayer, objectgroup, group, imagelayer
+	Name       string
+	Visible    bool
+	Opacity    float64
+	Properties Properties
+
+	// Данные конечной карты или чанки бесконечной
+	Width, Height int
+	Data          []GID
+	Chunks        []rawChunk
+
+	Objects []Object
+	Layers  []rawLayer
+}
+
+type rawChunk struct {
+	X, Y, Width, Height int
+	Data                []GID
+}
+
+type rawTileset struct {
+	FirstGID uint32
+	// Source — путь к внешнему набору; остальные поля тогда берутся из него
+	Source string
+	Tileset
+}
+
+// build собирает карту; dir — каталог файла карты
+func (r *rawMap) build(fsys fs.FS, dir string) (*Map, error) {
+	if r.Orientation != "" && r.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("%w: %s orientation", ErrUnsupported, r.Orientation)
+	}
+	if r.TileWidth <= 0 || r.TileHeight <= 0 {
+		return nil, fmt.Errorf("bad tile size %dx%d", r.TileWidth, r.TileHeight)
+	}
+
+	m := &Map{
+		TileWidth:  r.TileWidth,
+		TileHeight: r.TileHeight,
+		Properties: r.Properties,
+	}
+	if !r.Infinite {
+		m.Bounds = image.Rect(0, 0, r.Width, r.Height)
+	}
+
+	for _, rt := range r.Tilesets {
+		ts, err := loadTileset(fsys, dir, rt)
+		if err != nil {
+			return nil, err
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	sort.Slice(m.Tilesets, func(i, j int) bool {
+		return m.Tilesets[i].FirstGID < m.Tilesets[j].FirstGID
+	})
+
+	if err := m.addLayers(r.Layers, r.Infinite, true, 1); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// addLayers разворачивает дерево слоёв; видимость и прозрачность групп
+// переходят на вложенные слои
+func (m *Map) addLayers(layers []rawLayer, infinite, visible bool, opacity float64) error {
+	for _, rl := range layers {
+		switch rl.Type {
+		case "tilelayer":
+			l := newLayer(rl.Name, rl.Properties)
+			l.Visible = visible && rl.Visible
+			l.Opacity = opacity * rl.Opacity
+			if infinite {
+				for _, c := range rl.Chunks {
+					if len(c.Data) != c.Width*c.Height {
+						return fmt.Errorf("layer %q: chunk %d,%d has %d tiles, want %d", rl.Name, c.X, c.Y, len(c.Data), c.Width*c.Height)
+					}
+					m.fill(l, c.X, c.Y, c.Width, c.Height, c.Data)
+				}
+			} else {
+				if len(rl.Data) != rl.Width*rl.Height {
+					return fmt.Errorf("layer %q: has %d tiles, want %d", rl.Name, len(rl.Data), rl.Width*rl.Height)
+				}
+				m.fill(l, 0, 0, rl.Width, rl.Height, rl.Data)
+			}
+			m.Layers = append(m.Layers, l)
+		case "objectgroup":
+			for _, o := range rl.Objects {
+				o.Layer = rl.Name
+				m.Objects = append(m.Objects, o)
+			}
+		case "group":
+			if err := m.addLayers(rl.Layers, infinite, visible && rl.Visible, opacity*rl.Opacity); err != nil {
+				return err
+			}
+		}
+		// Слои-картинки не поддерживаются и пропускаются
+	}
+	return nil
+}
+
+// fill переносит прямоугольник тайлов в слой и расширяет границы
+// бесконечной карты
+func (m *Map) fill(l *Layer, x0, y0, w, h int, data []GID) {
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			gid := data[y*w+x]
+			if gid == 0 {
+				continue
+			}
+			l.set(x0+x, y0+y, gid)
+			m.Bounds = m.Bounds.Union(image.Rect(x0+x, y0+y, x0+x+1, y0+y+1))
+		}
+	}
+}
+
+// loadTileset читает внешний набор, если он задан ссылкой, и проверяет атлас
+func loadTileset(fsys fs.FS, dir string, rt rawTileset) (*Tileset, error) {
+	ts := rt.Tileset
+	if rt.Source != "" {
+		name := path.Join(dir, rt.Source)
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("tileset: %w", err)
+		}
+		var ext rawTileset
+		switch strings.ToLower(path.Ext(name)) {
+		case ".tsj", ".json":
+			var jt jsonTileset
+			if err = json.Unmarshal(data, &jt); err == nil {
+				ext = jt.raw()
+			}
+		case ".tsx":
+			var n xmlNode
+			if err = xml.Unmarshal(data, &n); err == nil {
+				ext = n.tileset()
+			}
+		default:
+			err = fmt.Errorf("%w: tileset format %q", ErrUnsupported, path.Ext(name))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tileset %s: %w", name, err)
+		}
+		ts = ext.Tileset
+		dir = path.Dir(name)
+	}
+	ts.FirstGID = rt.FirstGID
+
+	if ts.Image == "" {
+		return nil, fmt.Errorf("%w: tileset %q is an image collection", ErrUnsupported, ts.Name)
+	}
+	ts.Image = path.Join(dir, ts.Image)
+	if ts.TileWidth <= 0 || ts.TileHeight <= 0 {
+		return nil, fmt.Errorf("tileset %q: bad tile size %dx%d", ts.Name, ts.TileWidth, ts.TileHeight)
+	}
+	if ts.Columns <= 0 {
+		ts.Columns = (ts.ImageWidth - 2*ts.Margin + ts.Spacing) / (ts.TileWidth + ts.Spacing)
+	}
+	if ts.Columns <= 0 {
+		return nil, fmt.Errorf("tileset %q: no columns", ts.Name)
+	}
+	return &ts, nil
+}
+
+// decodeData разбирает данные слоя или чанка в CSV или base64
+func decodeData(encoding, compression, text string) ([]GID, error) {
+	switch encoding {
+	case "csv":
+		var out []GID
+		for _, field := range strings.Split(text, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			v, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("csv data: %w", err)
+			}
+			out = append(out, GID(v))
+		}
+		return out, nil
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("base64 data: %w", err)
+		}
+		var r io.Reader = bytes.NewReader(raw)
+		switch compression {
+		case "":
+		case "gzip":
+			if r, err = gzip.NewReader(r); err != nil {
+				return nil, fmt.Errorf("gzip data: %w", err)
+			}
+		case "zlib":
+			if r, err = zlib.NewReader(r); err != nil {
+				return nil, fmt.Errorf("zlib data: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s compression", ErrUnsupported, compression)
+		}
+		if raw, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("%s data: %w", compression, err)
+		}
+		if len(raw)%4 != 0 {
+			return nil, fmt.Errorf("base64 data: %d bytes is not a whole number of tiles", len(raw))
+		}
+		out := make([]GID, len(raw)/4)
+		for i := range out {
+			out[i] = GID(binary.LittleEndian.Uint32(raw[i*4:]))
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("%w: %q encoding", ErrUnsupported, encoding)
+	}
+}
+
+// === TMJ ===
+
+type jsonProperty struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+func jsonProperties(list []jsonProperty) Properties {
+	if len(list) == 0 {
+		return nil
+	}
+	props := make(Properties, len(list))
+	for _, p := range list {
+		props[p.Name] = fmt.Sprint(p.Value)
+	}
+	return props
+}
+
+type jsonMap struct {
+	Orientation string         `json:"orientation"`
+	Infinite    bool           `json:"infinite"`
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	TileWidth   int            `json:"tilewidth"`
+	TileHeight  int            `json:"tileheight"`
+	Tilesets    []jsonTileset  `json:"tilesets"`
+	Layers      []jsonLayer    `json:"layers"`
+	Properties  []jsonProperty `json:"properties"`
+}
+
+type jsonTileset struct {
+	FirstGID    uint32         `json:"firstgid"`
+	Source      string         `json:"source"`
+	Name        string         `json:"name"`
+	TileWidth   int            `json:"tilewidth"`
+	TileHeight  int            `json:"tileheight"`
+	TileCount   int            `json:"tilecount"`
+	Columns     int            `json:"columns"`
+	Margin      int            `json:"margin"`
+	Spacing     int            `json:"spacing"`
+	Image       string         `json:"image"`
+	ImageWidth  int            `json:"imagewidth"`
+	ImageHeight int            `json:"imageheight"`
+	Properties  []jsonProperty `json:"properties"`
+}
+
+func (t jsonTileset) raw() rawTileset {
+	return rawTileset{
+		FirstGID: t.FirstGID,
+		Source:   t.Source,
+		Tileset: Tileset{
+			Name:        t.Name,
+			TileWidth:   t.TileWidth,
+			TileHeight:  t.TileHeight,
+			TileCount:   t.TileCount,
+			Columns:     t.Columns,
+			Margin:      t.Margin,
+			Spacing:     t.Spacing,
+			Image:       t.Image,
+			ImageWidth:  t.ImageWidth,
+			ImageHeight: t.ImageHeight,
+		},
+	}
+}
+
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Visible     *bool           `json:"visible"`
+	Opacity     *float64        `json:"opacity"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Data        json.RawMessage `json:"data"`
+	Chunks      []jsonChunk     `json:"chunks"`
+	Objects     []jsonObject    `json:"objects"`
+	Layers      []jsonLayer     `json:"layers"`
+	Properties  []jsonProperty  `json:"properties"`
+}
+
+type jsonChunk struct {
+	X      int             `json:"x"`
+	Y      int             `json:"y"`
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type jsonObject struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	X          float64        `json:"x"`
+	Y          float64        `json:"y"`
+	Width      float64        `json:"width"`
+	Height     float64        `json:"height"`
+	Properties []jsonProperty `json:"properties"`
+}
+
+func (r *rawMap) fromJSON(data []byte) error {
+	var jm jsonMap
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return err
+	}
+	*r = rawMap{
+		Orientation: jm.Orientation,
+		Infinite:    jm.Infinite,
+		Width:       jm.Width,
+		Height:      jm.Height,
+		TileWidth:   jm.TileWidth,
+		TileHeight:  jm.TileHeight,
+		Properties:  jsonProperties(jm.Properties),
+	}
+	for _, t := range jm.Tilesets {
+		r.Tilesets = append(r.Tilesets, t.raw())
+	}
+	layers, err := jsonLayers(jm.Layers)
+	r.Layers = layers
+	return err
+}
+
+func jsonLayers(list []jsonLayer) ([]rawLayer, error) {
+	var out []rawLayer
+	for _, jl := range list {
+		rl := rawLayer{
+			Type:       jl.Type,
+			Name:       jl.Name,
+			Visible:    jl.Visible == nil || *jl.Visible,
+			Opacity:    1,
+			Width:      jl.Width,
+			Height:     jl.Height,
+			Properties: jsonProperties(jl.Properties),
+		}
+		if jl.Opacity != nil {
+			rl.Opacity = *jl.Opacity
+		}
+
+		var err error
+		if len(jl.Data) > 0 {
+			if rl.Data, err = jsonData(jl.Encoding, jl.Compression, jl.Data); err != nil {
+				return nil, fmt.Errorf("layer %q: %w", jl.Name, err)
+			}
+		}
+		for _, jc := range jl.Chunks {
+			c := rawChunk{X: jc.X, Y: jc.Y, Width: jc.Width, Height: jc.Height}
+			if c.Data, err = jsonData(jl.Encoding, jl.Compression, jc.Data); err != nil {
+				return nil, fmt.Errorf("layer %q: %w", jl.Name, err)
+			}
+			rl.Chunks = append(rl.Chunks, c)
+		}
+		for _, jo := range jl.Objects {
+			typ := jo.Type
+			if typ == "" {
+				typ = jo.Class
+			}
+			rl.Objects = append(rl.Objects, Object{
+				ID:         jo.ID,
+				Name:       jo.Name,
+				Type:       typ,
+				X:          jo.X,
+				Y:          jo.Y,
+				Width:      jo.Width,
+				Height:     jo.Height,
+				Properties: jsonProperties(jo.Properties),
+			})
+		}
+		if rl.Layers, err = jsonLayers(jl.Layers); err != nil {
+			return nil, err
+		}
+		out = append(out, rl)
+	}
+	return out, nil
+}
+
+// jsonData разбирает данные слоя: массив чисел или строку base64
+func jsonData(encoding, compression string, data json.RawMessage) ([]GID, error) {
+	if encoding == "" || encoding == "csv" {
+		var out []GID
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil, fmt.Errorf("data: %w", err)
+		}
+		return out, nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return nil, fmt.Errorf("data: %w", err)
+	}
+	return decodeData(encoding, compression, text)
+}
+
+// === TMX ===
+
+// xmlNode — элемент XML с атрибутами и детьми в порядке файла: порядок
+// слоёв разных типов в TMX важен, а обычный разбор в структуры его теряет
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Text    string     `xml:",chardata"`
+	Nodes   []xmlNode  `xml:",any"`
+}
+
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *xmlNode) int(name string) int {
+	v, _ := strconv.Atoi(n.attr(name))
+	return v
+}
+
+func (n *xmlNode) float(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(n.attr(name), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func (n *xmlNode) child(name string) *xmlNode {
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == name {
+			return &n.Nodes[i]
+		}
+	}
+	return nil
+}
+
+func (n *xmlNode) properties() Properties {
+	p := n.child("properties")
+	if p == nil {
+		return nil
+	}
+	props := make(Properties)
+	for _, prop := range p.Nodes {
+		if prop.XMLName.Local != "property" {
+			continue
+		}
+		v := prop.attr("value")
+		if v == "" {
+			// Многострочные строковые свойства записываются текстом элемента
+			v = prop.Text
+		}
+		props[prop.attr("name")] = v
+	}
+	return props
+}
+
+func (n *xmlNode) tileset() rawTileset {
+	rt := rawTileset{
+		FirstGID: uint32(n.int("firstgid")),
+		Source:   n.attr("source"),
+		Tileset: Tileset{
+			Name:       n.attr("name"),
+			TileWidth:  n.int("tilewidth"),
+			TileHeight: n.int("tileheight"),
+			TileCount:  n.int("tilecount"),
+			Columns:    n.int("columns"),
+			Margin:     n.int("margin"),
+			Spacing:    n.int("spacing"),
+		},
+	}
+	if img := n.child("image"); img != nil {
+		rt.Image = img.attr("source")
+		rt.ImageWidth = img.int("width")
+		rt.ImageHeight = img.int("height")
+	}
+	return rt
+}
+
+func (r *rawMap) fromXML(data []byte) error {
+	var n xmlNode
+	if err := xml.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	if n.XMLName.Local != "map" {
+		return fmt.Errorf("root element is <%s>, want <map>", n.XMLName.Local)
+	}
+	*r = rawMap{
+		Orientation: n.attr("orientation"),
+		Infinite:    n.attr("infinite") == "1",
+		Width:       n.int("width"),
+		Height:      n.int("height"),
+		TileWidth:   n.int("tilewidth"),
+		TileHeight:  n.int("tileheight"),
+		Properties:  n.properties(),
+	}
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == "tileset" {
+			r.Tilesets = append(r.Tilesets, n.Nodes[i].tileset())
+		}
+	}
+	layers, err := xmlLayers(&n)
+	r.Layers = layers
+	return err
+}
+
+func xmlLayers(parent *xmlNode) ([]rawLayer, error) {
+	var out []rawLayer
+	for i := range parent.Nodes {
+		n := &parent.Nodes[i]
+		rl := rawLayer{
+			Name:       n.attr("name"),
+			Visible:    n.attr("visible") != "0",
+			Opacity:    n.float("opacity", 1),
+			Width:      n.int("width"),
+			Height:     n.int("height"),
+			Properties: n.properties(),
+		}
+
+		switch n.XMLName.Local {
+		case "layer":
+			rl.Type = "tilelayer"
+			if err := xmlTileData(n.child("data"), &rl); err != nil {
+				return nil, fmt.Errorf("layer %q: %w", rl.Name, err)
+			}
+		case "objectgroup":
+			rl.Type = "objectgroup"
+			for j := range n.Nodes {
+				o := &n.Nodes[j]
+				if o.XMLName.Local != "object" {
+					continue
+				}
+				typ := o.attr("type")
+				if typ == "" {
+					typ = o.attr("class")
+				}
+				rl.Objects = append(rl.Objects, Object{
+					ID:         o.int("id"),
+					Name:       o.attr("name"),
+					Type:       typ,
+					X:          o.float("x", 0),
+					Y:          o.float("y", 0),
+					Width:      o.float("width", 0),
+					Height:     o.float("height", 0),
+					Properties: o.properties(),
+				})
+			}
+		case "group":
+			rl.Type = "group"
+			layers, err := xmlLayers(n)
+			if err != nil {
+				return nil, err
+			}
+			rl.Layers = layers
+		default:
+			continue
+		}
+		out = append(out, rl)
+	}
+	return out, nil
+}
+
+// xmlTileData разбирает <data>: текст, чанки или устаревшие элементы <tile>
+func xmlTileData(data *xmlNode, rl *rawLayer) error {
+	if data == nil {
+		return errors.New("missing <data>")
+	}
+	encoding, compression := data.attr("encoding"), data.attr("compression")
+
+	parse := func(n *xmlNode) ([]GID, error) {
+		if encoding == "" {
+			var out []GID
+			for _, t := range n.Nodes {
+				if t.XMLName.Local == "tile" {
+					out = append(out, GID(t.int("gid")))
+				}
+			}
+			return out, nil
+		}
+		return decodeData(encoding, compression, n.Text)
+	}
+
+	hasChunks := false
+	for i := range data.Nodes {
+		c := &data.Nodes[i]
+		if c.XMLName.Local != "chunk" {
+			continue
+		}
+		hasChunks = true
+		gids, err := parse(c)
+		if err != nil {
+			return err
+		}
+		rl.Chunks = append(rl.Chunks, rawChunk{X: c.int("x"), Y: c.int("y"), Width: c.int("width"), Height: c.int("height"), Data: gids})
+	}
+	if hasChunks {
+		return nil
+	}
+
+	gids, err := parse(data)
+	rl.Data = gids
+	return err
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"image"
+	"math"
+)
+
+// Anchor — точка экрана или прямоугольника в долях ширины и высоты:
+// {0, 0} — левый верхний угол, {1, 1} — правый нижний
+type Anchor struct {
+	X, Y float64
+}
+
+// Якоря углов, середин сторон и центра
+var (
+	TopLeft     = Anchor{0, 0}
+	Top         = Anchor{0.5, 0}
+	TopRight    = Anchor{1, 0}
+	Left        = Anchor{0, 0.5}
+	Center      = Anchor{0.5, 0.5}
+	Right       = Anchor{1, 0.5}
+	BottomLeft  = Anchor{0, 1}
+	Bottom      = Anchor{0.5, 1}
+	BottomRight = Anchor{1, 1}
+)
+
+// Point — точка относительно якоря экрана. Смещение задаётся в пикселях
+// интерфейса 1280×720 и умножается на масштаб темы.
+type Point struct {
+	Anchor Anchor
+	X, Y   int
+}
+
+// At — точка со смещением x, y от якоря
+func At(a Anchor, x, y int) Point {
+	return Point{Anchor: a, X: x, Y: y}
+}
+
+// Resolve переводит точку в координаты экрана темы
+func (p Point) Resolve(t *Theme) image.Point {
+	s := t.Screen
+	return image.Pt(
+		s.Min.X+int(math.Round(float64(s.Dx())*p.Anchor.X))+t.Px(p.X),
+		s.Min.Y+int(math.Round(float64(s.Dy())*p.Anchor.Y))+t.Px(p.Y),
+	)
+}
+
+// Box — прямоугольник W×H, точка Pivot которого (в долях его размера)
+// совпадает с точкой At: Box{At: At(Center, 0, 0), W: 200, H: 50, Pivot: Center}
+// — кнопка по центру экрана
+type Box struct {
+	At    Point
+	W, H  int
+	Pivot Anchor
+}
+
+// Resolve переводит прямоугольник в координаты экрана темы
+func (b Box) Resolve(t *Theme) image.Rectangle {
+	p := b.At.Resolve(t)
+	w, h := t.Px(b.W), t.Px(b.H)
+	x := p.X - int(math.Round(float64(w)*b.Pivot.X))
+	y := p.Y - int(math.Round(float64(h)*b.Pivot.Y))
+	return image.Rect(x, y, x+w, y+h)
+}
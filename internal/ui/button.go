@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Button — кнопка с рамкой фиксированного размера
+type Button struct {
+	// Key — ключ локализации; Text — текст без перевода (например, название
+	// языка); Dynamic, если задан, вычисляет текст каждый кадр
+	Key     string
+	Text    string
+	Dynamic func() string
+
+	Box     Box
+	OnClick func()
+
+	rect    image.Rectangle
+	hovered bool
+	focused bool
+}
+
+func (b *Button) label(t *Theme) string {
+	if b.Dynamic != nil {
+		return b.Dynamic()
+	}
+	if b.Key != "" {
+		return t.Text(b.Key)
+	}
+	return b.Text
+}
+
+func (b *Button) Layout(t *Theme) {
+	b.rect = b.Box.Resolve(t)
+}
+
+func (b *Button) Bounds() image.Rectangle {
+	return b.rect
+}
+
+func (b *Button) Update(t *Theme, p Pointer) {
+	b.hovered = p.In(b.rect)
+	if b.hovered && p.Pressed {
+		b.Activate()
+	}
+}
+
+func (b *Button) SetFocused(focused bool) {
+	b.focused = focused
+}
+
+func (b *Button) Activate() {
+	if b.OnClick != nil {
+		b.OnClick()
+	}
+}
+
+func (b *Button) Draw(screen *ebiten.Image, t *Theme) {
+	bg, border, textColor := buttonBg, buttonBorder, buttonText
+	if b.hovered || b.focused {
+		bg, border, textColor = buttonHoverBg, buttonHoverEdge, buttonActiveText
+	}
+	b.drawWith(screen, t, bg, border, textColor)
+}
+
+func (b *Button) drawWith(screen *ebiten.Image, t *Theme, bg, border, textColor color.Color) {
+	drawBox(screen, b.rect, bg, border)
+
+	s := b.label(t)
+	x, y := centerTextIn(t.BodyFont, s, b.rect)
+	drawTextShadow(screen, s, t.BodyFont, x, y, t.Px(2), textColor, 150)
+}
+
+// Toggle — кнопка-переключатель; выбранное состояние читается из Checked
+type Toggle struct {
+	Button
+	Checked func() bool
+}
+
+func (tg *Toggle) Draw(screen *ebiten.Image, t *Theme) {
+	checked := tg.Checked != nil && tg.Checked()
+	if !checked {
+		tg.Button.Draw(screen, t)
+		return
+	}
+
+	tg.drawWith(screen, t, buttonActiveBg, buttonHoverEdge, buttonActiveText)
+
+	dotX := float64(tg.rect.Min.X + t.Px(15))
+	dotY := float64(tg.rect.Min.Y + tg.rect.Dy()/2)
+	DrawGlowingDot(screen, dotX, dotY, t.Glow)
+}
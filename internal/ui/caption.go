@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// captionPadding — отступ подложки субтитров от текста
+const captionPadding = 10
+
+// Caption — субтитры: строки по центру над нижней границей на тёмной подложке.
+// Pos — середина нижнего края подложки.
+type Caption struct {
+	Pos Point
+
+	// Lines возвращает реплики, видимые в этом кадре; в реплике может быть несколько строк
+	Lines func() []string
+
+	rect            image.Rectangle
+	centerX, bottom int
+}
+
+func (c *Caption) Layout(t *Theme) {
+	p := c.Pos.Resolve(t)
+	c.centerX, c.bottom = p.X, p.Y
+	c.rect = image.Rect(c.centerX, c.bottom, c.centerX, c.bottom)
+}
+
+func (c *Caption) Bounds() image.Rectangle {
+	return c.rect
+}
+
+func (c *Caption) Update(t *Theme, p Pointer) {}
+
+func (c *Caption) Draw(screen *ebiten.Image, t *Theme) {
+	if c.Lines == nil {
+		return
+	}
+
+	var lines []string
+	for _, cue := range c.Lines() {
+		lines = append(lines, strings.Split(cue, "\n")...)
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	face := t.BodyFont
+	m := face.Metrics()
+	lineHeight := m.Height.Ceil()
+
+	width := 0
+	for _, line := range lines {
+		if w, _ := textSize(face, line); w > width {
+			width = w
+		}
+	}
+
+	pad := t.Px(captionPadding)
+	height := lineHeight * len(lines)
+	top := c.bottom - height - pad*2
+	ebitenutil.DrawRect(screen,
+		float64(c.centerX-width/2-pad), float64(top),
+		float64(width+pad*2), float64(height+pad*2),
+		color.RGBA{0, 0, 0, 150})
+
+	y := top + pad + m.Ascent.Ceil()
+	for _, line := range lines {
+		w, _ := textSize(face, line)
+		drawTextShadow(screen, line, face, c.centerX-w/2, y, t.Px(2), color.RGBA{240, 235, 220, 255}, 200)
+		y += lineHeight
+	}
+}
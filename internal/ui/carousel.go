@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// carouselArrowWidth — ширина зон стрелок по краям карусели
+const carouselArrowWidth = 44
+
+// maxCarouselDots — при большем числе вариантов вместо точек выводится "3 / 12"
+const maxCarouselDots = 10
+
+// Carousel — выбор одного значения из списка любой длины: стрелки по краям
+// листают варианты по кругу, под рамкой показывается позиция в списке
+type Carousel struct {
+	Box Box
+
+	// Items возвращает подписи вариантов; Index — выбранный вариант
+	Items    func() []string
+	Index    func() int
+	OnChange func(i int)
+
+	rect    image.Rectangle
+	arrow   int
+	hovered bool
+	focused bool
+}
+
+func (c *Carousel) Layout(t *Theme) {
+	c.rect = c.Box.Resolve(t)
+	c.arrow = t.Px(carouselArrowWidth)
+}
+
+func (c *Carousel) Bounds() image.Rectangle {
+	return c.rect
+}
+
+func (c *Carousel) leftArrow() image.Rectangle {
+	return image.Rect(c.rect.Min.X, c.rect.Min.Y, c.rect.Min.X+c.arrow, c.rect.Max.Y)
+}
+
+func (c *Carousel) rightArrow() image.Rectangle {
+	return image.Rect(c.rect.Max.X-c.arrow, c.rect.Min.Y, c.rect.Max.X, c.rect.Max.Y)
+}
+
+func (c *Carousel) Update(t *Theme, p Pointer) {
+	c.hovered = p.In(c.rect)
+	if !c.hovered || !p.Pressed {
+		return
+	}
+
+	if p.In(c.leftArrow()) {
+		c.shift(-1)
+	} else {
+		c.shift(1)
+	}
+}
+
+func (c *Carousel) shift(delta int) {
+	n := len(c.items())
+	if n == 0 || c.OnChange == nil {
+		return
+	}
+	i := (c.index() + delta) % n
+	if i < 0 {
+		i += n
+	}
+	c.OnChange(i)
+}
+
+func (c *Carousel) items() []string {
+	if c.Items == nil {
+		return nil
+	}
+	return c.Items()
+}
+
+func (c *Carousel) index() int {
+	if c.Index == nil {
+		return 0
+	}
+	return c.Index()
+}
+
+// Step листает варианты: знак delta задаёт направление
+func (c *Carousel) Step(delta float64) {
+	switch {
+	case delta < 0:
+		c.shift(-1)
+	case delta > 0:
+		c.shift(1)
+	}
+}
+
+func (c *Carousel) SetFocused(focused bool) {
+	c.focused = focused
+}
+
+func (c *Carousel) Activate() {
+	c.shift(1)
+}
+
+func (c *Carousel) Draw(screen *ebiten.Image, t *Theme) {
+	bg, border, textColor := buttonBg, buttonBorder, buttonText
+	if c.hovered || c.focused {
+		bg, border, textColor = buttonHoverBg, buttonHoverEdge, buttonActiveText
+	}
+	drawBox(screen, c.rect, bg, border)
+
+	items := c.items()
+	if len(items) == 0 {
+		return
+	}
+	index := c.index()
+	if index < 0 || index >= len(items) {
+		index = 0
+	}
+
+	// Стрелки
+	for _, arrow := range []struct {
+		label string
+		rect  image.Rectangle
+	}{{"<", c.leftArrow()}, {">", c.rightArrow()}} {
+		x, y := centerTextIn(t.BodyFont, arrow.label, arrow.rect)
+		text.Draw(screen, arrow.label, t.BodyFont, x, y, textColor)
+	}
+
+	// Выбранный вариант
+	label := items[index]
+	x, y := centerTextIn(t.BodyFont, label, c.rect)
+	drawTextShadow(screen, label, t.BodyFont, x, y, t.Px(2), textColor, 150)
+
+	// Позиция в списке
+	indicatorY := c.rect.Max.Y + t.Px(14)
+	if len(items) <= maxCarouselDots {
+		spacing := t.Px(14)
+		startX := c.rect.Min.X + c.rect.Dx()/2 - (len(items)-1)*spacing/2
+		for i := range items {
+			dotX := float64(startX + i*spacing)
+			if i == index {
+				DrawGlowingDot(screen, dotX, float64(indicatorY), t.Glow)
+			} else {
+				ebitenutil.DrawRect(screen, dotX-2, float64(indicatorY)-2, 4, 4, color.RGBA{120, 100, 160, 200})
+			}
+		}
+		return
+	}
+
+	pos := fmt.Sprintf("%d / %d", index+1, len(items))
+	w, h := textSize(t.BodyFont, pos)
+	text.Draw(screen, pos, t.BodyFont, c.rect.Min.X+c.rect.Dx()/2-w/2, indicatorY+h, color.RGBA{150, 140, 130, 200})
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+// Цвета кнопок в обычном, наведённом и выбранном состоянии
+var (
+	buttonBg         = color.RGBA{50, 40, 80, 255}
+	buttonBorder     = color.RGBA{100, 80, 140, 200}
+	buttonText       = color.RGBA{200, 200, 200, 255}
+	buttonHoverBg    = color.RGBA{80, 60, 120, 255}
+	buttonHoverEdge  = color.RGBA{150, 120, 200, 255}
+	buttonActiveBg   = color.RGBA{100, 80, 150, 255}
+	buttonActiveText = color.RGBA{255, 255, 255, 255}
+)
+
+// DrawGlowingDot рисует светящуюся точку
+func DrawGlowingDot(screen *ebiten.Image, x, y, intensity float64) {
+	for i := 0; i < 4; i++ {
+		size := float64(8 - i*2)
+		alpha := uint8(50 * intensity * float64(4-i) / 4.0)
+		offset := size / 2
+		ebitenutil.DrawRect(screen, x-offset, y-offset, size, size, color.RGBA{200, 160, 255, alpha})
+	}
+
+	coreAlpha := uint8(220 + 35*intensity)
+	ebitenutil.DrawRect(screen, x-1, y-1, 2, 2, color.RGBA{240, 220, 255, coreAlpha})
+}
+
+// drawBox рисует прямоугольник с рамкой толщиной 2px
+func drawBox(screen *ebiten.Image, r image.Rectangle, bg, border color.Color) {
+	x, y := float64(r.Min.X), float64(r.Min.Y)
+	w, h := float64(r.Dx()), float64(r.Dy())
+
+	ebitenutil.DrawRect(screen, x, y, w, h, bg)
+	ebitenutil.DrawRect(screen, x, y, w, 2, border)
+	ebitenutil.DrawRect(screen, x, y+h-2, w, 2, border)
+	ebitenutil.DrawRect(screen, x, y, 2, h, border)
+	ebitenutil.DrawRect(screen, x+w-2, y, 2, h, border)
+}
+
+// drawTextShadow рисует текст с тенью, смещённой на offset пикселей
+func drawTextShadow(screen *ebiten.Image, s string, face font.Face, x, y, offset int, clr color.Color, shadowAlpha uint8) {
+	text.Draw(screen, s, face, x+offset, y+offset, color.RGBA{0, 0, 0, shadowAlpha})
+	text.Draw(screen, s, face, x, y, clr)
+}
+
+// textSize возвращает ширину и высоту строки
+func textSize(face font.Face, s string) (int, int) {
+	b := text.BoundString(face, s)
+	return b.Max.X - b.Min.X, b.Max.Y - b.Min.Y
+}
+
+// centerTextIn возвращает позицию базовой линии для текста, центрированного в прямоугольнике
+func centerTextIn(face font.Face, s string, r image.Rectangle) (int, int) {
+	w, h := textSize(face, s)
+	return r.Min.X + r.Dx()/2 - w/2, r.Min.Y + r.Dy()/2 + h/3
+}
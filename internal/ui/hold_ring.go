@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// ringSegments — из скольких отрезков собирается окружность кольца
+const ringSegments = 48
+
+// HoldRing — кольцо прогресса удержания кнопки с подписью слева.
+// Кольцо заполняется по часовой стрелке от верхней точки; пока кнопку
+// не держат, не рисуется ничего.
+type HoldRing struct {
+	Pos    Point // центр кольца
+	Radius int
+
+	// Key — ключ локализации подписи, например "Hold to skip"
+	Key      string
+	Progress func() float64
+
+	rect   image.Rectangle
+	center image.Point
+	radius int
+}
+
+func (r *HoldRing) Layout(t *Theme) {
+	r.center = r.Pos.Resolve(t)
+	r.radius = t.Px(r.Radius)
+	r.rect = image.Rectangle{Min: r.center, Max: r.center}.Inset(-r.radius)
+}
+
+func (r *HoldRing) Bounds() image.Rectangle {
+	return r.rect
+}
+
+func (r *HoldRing) Update(t *Theme, p Pointer) {}
+
+func (r *HoldRing) Draw(screen *ebiten.Image, t *Theme) {
+	progress := 0.0
+	if r.Progress != nil {
+		progress = math.Max(0, math.Min(1, r.Progress()))
+	}
+	if progress == 0 {
+		return
+	}
+
+	cx, cy := float64(r.center.X), float64(r.center.Y)
+	filled := int(math.Round(progress * ringSegments))
+	for i := 0; i < ringSegments; i++ {
+		clr := color.RGBA{90, 80, 110, 160}
+		if i < filled {
+			clr = color.RGBA{200, 160, 255, 255}
+		}
+
+		a0 := 2*math.Pi*float64(i)/ringSegments - math.Pi/2
+		a1 := 2*math.Pi*float64(i+1)/ringSegments - math.Pi/2
+		// Толщина кольца — несколько концентрических отрезков
+		for w := -1.5; w <= 1.5; w += 0.75 {
+			rad := float64(r.radius) + w
+			ebitenutil.DrawLine(screen,
+				cx+rad*math.Cos(a0), cy+rad*math.Sin(a0),
+				cx+rad*math.Cos(a1), cy+rad*math.Sin(a1), clr)
+		}
+	}
+
+	end := 2*math.Pi*progress - math.Pi/2
+	DrawGlowingDot(screen, cx+float64(r.radius)*math.Cos(end), cy+float64(r.radius)*math.Sin(end), t.Glow)
+
+	if r.Key != "" {
+		label := t.Text(r.Key)
+		w, h := textSize(t.BodyFont, label)
+		text.Draw(screen, label, t.BodyFont, r.center.X-r.radius-t.Px(12)-w, r.center.Y+h/2, color.RGBA{200, 190, 170, 220})
+	}
+}
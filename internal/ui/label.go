@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Align — горизонтальное выравнивание текста относительно точки привязки
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Label — текстовая надпись. Pos — точка привязки на базовой линии.
+type Label struct {
+	// Key — ключ локализации; Dynamic, если задан, вычисляет текст каждый кадр
+	Key     string
+	Dynamic func() string
+
+	Font  FontRole
+	Pos   Point
+	Align Align
+	Color color.Color
+
+	ShadowOffset int
+	ShadowAlpha  uint8
+
+	// Underline рисует декоративную линию под текстом на расстоянии UnderlineOffset от базовой линии
+	Underline       bool
+	UnderlineOffset int
+
+	rect  image.Rectangle
+	x, y  int
+	drawX int
+}
+
+func (l *Label) text(t *Theme) string {
+	if l.Dynamic != nil {
+		return l.Dynamic()
+	}
+	return t.Text(l.Key)
+}
+
+func (l *Label) Layout(t *Theme) {
+	s := l.text(t)
+	b := text.BoundString(t.Face(l.Font), s)
+
+	p := l.Pos.Resolve(t)
+	l.x, l.y = p.X, p.Y
+	l.drawX = l.x
+	switch l.Align {
+	case AlignCenter:
+		l.drawX = l.x - b.Dx()/2
+	case AlignRight:
+		l.drawX = l.x - b.Dx()
+	}
+	l.rect = b.Add(image.Pt(l.drawX, l.y))
+}
+
+func (l *Label) Bounds() image.Rectangle {
+	return l.rect
+}
+
+func (l *Label) Update(t *Theme, p Pointer) {}
+
+func (l *Label) Draw(screen *ebiten.Image, t *Theme) {
+	s := l.text(t)
+	x := l.drawX
+	if l.Dynamic != nil && l.Align != AlignLeft {
+		// Динамический текст меняет ширину без перераскладки
+		w, _ := textSize(t.Face(l.Font), s)
+		x = l.x - w
+		if l.Align == AlignCenter {
+			x = l.x - w/2
+		}
+	}
+	drawTextShadow(screen, s, t.Face(l.Font), x, l.y, t.Px(l.ShadowOffset), l.Color, l.ShadowAlpha)
+
+	if l.Underline {
+		lineY := float64(l.y + t.Px(l.UnderlineOffset))
+		ebitenutil.DrawRect(screen, float64(l.rect.Min.X), lineY, float64(l.rect.Dx()), float64(t.Px(2)), color.RGBA{180, 170, 150, 100})
+	}
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// MenuEntry — пункт главного меню: текст с подсветкой и точкой слева, когда выбран.
+// Pos — начало базовой линии текста.
+type MenuEntry struct {
+	Key        string
+	Pos        Point
+	OnActivate func()
+
+	rect      image.Rectangle
+	x, y      int
+	textWidth int
+	focused   bool
+}
+
+func (m *MenuEntry) Layout(t *Theme) {
+	w, h := textSize(t.BodyFont, t.Text(m.Key))
+	m.textWidth = w
+	p := m.Pos.Resolve(t)
+	m.x, m.y = p.X, p.Y
+
+	// Прямоугольник захватывает точку слева и линию подсветки под текстом
+	m.rect = image.Rect(m.x-t.Px(30), m.y-h, m.x+w+t.Px(10), m.y+t.Px(10))
+}
+
+func (m *MenuEntry) Bounds() image.Rectangle {
+	return m.rect
+}
+
+func (m *MenuEntry) Update(t *Theme, p Pointer) {
+	if p.Pressed && p.In(m.rect) {
+		m.Activate()
+	}
+}
+
+func (m *MenuEntry) SetFocused(focused bool) {
+	m.focused = focused
+}
+
+func (m *MenuEntry) Activate() {
+	if m.OnActivate != nil {
+		m.OnActivate()
+	}
+}
+
+func (m *MenuEntry) Draw(screen *ebiten.Image, t *Theme) {
+	var textColor color.RGBA
+	if m.focused {
+		glowValue := uint8(220 + 35*t.Glow)
+
+		lineY := float64(m.y + t.Px(8))
+		lineWidth := float64(m.textWidth + t.Px(10))
+
+		for j := 0; j < 3; j++ {
+			glowAlpha := uint8(float64(60-j*15) * t.Glow)
+			ebitenutil.DrawRect(screen, float64(m.x-t.Px(5)-j), lineY+float64(j), lineWidth+float64(j*2), 1, color.RGBA{180, 140, 255, glowAlpha})
+		}
+
+		ebitenutil.DrawRect(screen, float64(m.x-t.Px(5)), lineY, lineWidth, float64(t.Px(2)), color.RGBA{200, 160, 255, uint8(200 * t.Glow)})
+
+		DrawGlowingDot(screen, float64(m.x-t.Px(25)), float64(m.y-t.Px(8)), t.Glow)
+
+		textColor = color.RGBA{glowValue, glowValue - 20, 255, 255}
+	} else {
+		textColor = color.RGBA{150, 140, 130, 200}
+	}
+
+	drawTextShadow(screen, t.Text(m.Key), t.BodyFont, m.x, m.y, t.Px(2), textColor, 100)
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Prompt — экранная подсказка: значок кнопки и подпись действия
+type Prompt struct {
+	Glyph   string
+	Gamepad bool
+	// Key — ключ локализации подписи действия
+	Key string
+}
+
+// padFaceColors — цвета лицевых кнопок геймпада
+var padFaceColors = map[string]color.RGBA{
+	"A": {60, 150, 70, 255},
+	"B": {170, 55, 55, 255},
+	"X": {50, 100, 180, 255},
+	"Y": {190, 160, 40, 255},
+}
+
+// PromptBar — строка подсказок управления; значки перестраиваются каждый кадр,
+// так как зависят от последнего использованного устройства.
+// Pos — начало базовой линии.
+type PromptBar struct {
+	Pos     Point
+	Prompts func() []Prompt
+
+	rect image.Rectangle
+	x, y int
+}
+
+const (
+	glyphPadding = 6
+	promptGap    = 28
+)
+
+func (pb *PromptBar) Layout(t *Theme) {
+	p := pb.Pos.Resolve(t)
+	pb.x, pb.y = p.X, p.Y
+	pb.rect = image.Rect(pb.x, pb.y-t.BodyFont.Metrics().Ascent.Ceil()-t.Px(glyphPadding), pb.x, pb.y+t.Px(glyphPadding))
+}
+
+func (pb *PromptBar) Bounds() image.Rectangle {
+	return pb.rect
+}
+
+func (pb *PromptBar) Update(t *Theme, p Pointer) {}
+
+func (pb *PromptBar) Draw(screen *ebiten.Image, t *Theme) {
+	if pb.Prompts == nil {
+		return
+	}
+
+	x := pb.x
+	for _, p := range pb.Prompts() {
+		x = drawGlyph(screen, t, p, x, pb.y)
+
+		label := t.Text(p.Key)
+		text.Draw(screen, label, t.BodyFont, x+t.Px(glyphPadding), pb.y, color.RGBA{150, 140, 130, 200})
+		w, _ := textSize(t.BodyFont, label)
+		x += t.Px(glyphPadding) + w + t.Px(promptGap)
+	}
+}
+
+// drawGlyph рисует значок кнопки и возвращает X его правого края
+func drawGlyph(screen *ebiten.Image, t *Theme, p Prompt, x, baseline int) int {
+	w, h := textSize(t.BodyFont, p.Glyph)
+	pad := t.Px(glyphPadding)
+	box := image.Rect(x, baseline-h-pad, x+w+pad*2, baseline+pad)
+
+	if p.Gamepad {
+		bg, ok := padFaceColors[p.Glyph]
+		if !ok {
+			bg = color.RGBA{70, 70, 80, 255}
+		}
+		drawBox(screen, box, bg, color.RGBA{230, 230, 230, 180})
+	} else {
+		drawBox(screen, box, buttonBg, buttonBorder)
+	}
+
+	text.Draw(screen, p.Glyph, t.BodyFont, x+pad, baseline, buttonActiveText)
+	return box.Max.X
+}
@@ -0,0 +1,126 @@
+package ui
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Root — корень дерева виджетов сцены: раскладка, мышь и фокус
+type Root struct {
+	Panel
+
+	Theme *Theme
+
+	focusList []Focusable
+	focus     int
+
+	lastX, lastY   int
+	pointerTracked bool
+}
+
+func NewRoot(theme *Theme) *Root {
+	return &Root{Theme: theme, focus: -1}
+}
+
+// Layout пересчитывает прямоугольники всего дерева и список фокусируемых виджетов
+func (r *Root) Layout() {
+	r.Panel.Layout(r.Theme)
+	r.focusList = r.Panel.focusables(r.focusList[:0])
+
+	if len(r.focusList) == 0 {
+		r.focus = -1
+	} else if r.focus >= len(r.focusList) {
+		r.focus = len(r.focusList) - 1
+	}
+	r.applyFocus()
+}
+
+// Update раздаёт состояние указателя виджетам.
+// Наведение курсора переносит фокус на виджет, но только когда курсор двигался,
+// чтобы не перебивать навигацию с клавиатуры.
+func (r *Root) Update(ptr Pointer) {
+	moved := r.pointerTracked && (ptr.X != r.lastX || ptr.Y != r.lastY)
+	r.lastX, r.lastY, r.pointerTracked = ptr.X, ptr.Y, true
+
+	if moved || ptr.Pressed {
+		for i, f := range r.focusList {
+			if ptr.In(f.Bounds()) {
+				r.SetFocus(i)
+				break
+			}
+		}
+	}
+
+	r.Panel.Update(r.Theme, ptr)
+}
+
+// Reset забывает положение курсора, чтобы курсор, оставшийся над виджетом
+// после смены сцены, не перехватил фокус у клавиатуры
+func (r *Root) Reset() {
+	r.pointerTracked = false
+}
+
+// Draw отрисовывает дерево
+func (r *Root) Draw(screen *ebiten.Image) {
+	r.Panel.Draw(screen, r.Theme)
+}
+
+// Focus возвращает индекс виджета в фокусе или -1
+func (r *Root) Focus() int {
+	return r.focus
+}
+
+// SetFocus переводит фокус на i-й фокусируемый виджет
+func (r *Root) SetFocus(i int) {
+	if i < 0 || i >= len(r.focusList) {
+		return
+	}
+	r.focus = i
+	r.applyFocus()
+}
+
+// FocusNext переводит фокус на следующий виджет по кругу
+func (r *Root) FocusNext() {
+	if len(r.focusList) == 0 {
+		return
+	}
+	r.SetFocus((r.focus + 1) % len(r.focusList))
+}
+
+// FocusPrev переводит фокус на предыдущий виджет по кругу
+func (r *Root) FocusPrev() {
+	if len(r.focusList) == 0 {
+		return
+	}
+	i := r.focus - 1
+	if i < 0 {
+		i = len(r.focusList) - 1
+	}
+	r.SetFocus(i)
+}
+
+// Focused возвращает виджет в фокусе или nil
+func (r *Root) Focused() Focusable {
+	if r.focus < 0 || r.focus >= len(r.focusList) {
+		return nil
+	}
+	return r.focusList[r.focus]
+}
+
+// Adjust изменяет значение виджета в фокусе, если он это поддерживает
+func (r *Root) Adjust(delta float64) {
+	if a, ok := r.Focused().(Adjustable); ok {
+		a.Step(delta)
+	}
+}
+
+// Activate активирует виджет в фокусе
+func (r *Root) Activate() {
+	if r.focus < 0 || r.focus >= len(r.focusList) {
+		return
+	}
+	r.focusList[r.focus].Activate()
+}
+
+func (r *Root) applyFocus() {
+	for i, f := range r.focusList {
+		f.SetFocused(i == r.focus)
+	}
+}
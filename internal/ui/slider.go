@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// knobSize — размер ползунка; на столько же зона попадания шире дорожки
+const knobSize = 12
+
+// Slider — горизонтальный ползунок значения 0..1 с подписью в процентах
+type Slider struct {
+	Box      Box
+	Value    func() float64
+	OnChange func(v float64)
+
+	// InlineValue выводит процент справа от дорожки, а не под ней
+	InlineValue bool
+
+	rect     image.Rectangle
+	knob     int
+	dragging bool
+	focused  bool
+}
+
+func (s *Slider) Layout(t *Theme) {
+	s.rect = s.Box.Resolve(t)
+	s.knob = t.Px(knobSize)
+}
+
+func (s *Slider) Bounds() image.Rectangle {
+	return s.rect
+}
+
+// hitRect — зона попадания мышью: дорожка, расширенная на размер ползунка
+func (s *Slider) hitRect() image.Rectangle {
+	return s.rect.Inset(-s.knob / 2)
+}
+
+// Dragging сообщает, перетаскивается ли ползунок
+func (s *Slider) Dragging() bool {
+	return s.dragging
+}
+
+func (s *Slider) Update(t *Theme, p Pointer) {
+	if p.Pressed && p.In(s.hitRect()) {
+		s.dragging = true
+	}
+	if !p.Down {
+		s.dragging = false
+	}
+	if s.dragging {
+		s.set(float64(p.X-s.rect.Min.X) / float64(s.rect.Dx()))
+	}
+}
+
+// Step сдвигает значение на delta
+func (s *Slider) Step(delta float64) {
+	s.set(s.value() + delta)
+}
+
+func (s *Slider) set(v float64) {
+	if v < 0 {
+		v = 0
+	}
+	if v > 1 {
+		v = 1
+	}
+	if s.OnChange != nil {
+		s.OnChange(v)
+	}
+}
+
+func (s *Slider) value() float64 {
+	if s.Value == nil {
+		return 0
+	}
+	return s.Value()
+}
+
+func (s *Slider) SetFocused(focused bool) {
+	s.focused = focused
+}
+
+func (s *Slider) Activate() {}
+
+func (s *Slider) Draw(screen *ebiten.Image, t *Theme) {
+	x, y := float64(s.rect.Min.X), float64(s.rect.Min.Y)
+	w, h := float64(s.rect.Dx()), float64(s.rect.Dy())
+	v := s.value()
+
+	// Фон и заполненная часть
+	ebitenutil.DrawRect(screen, x, y, w, h, color.RGBA{40, 30, 60, 255})
+	filledWidth := w * v
+	ebitenutil.DrawRect(screen, x, y, filledWidth, h, color.RGBA{100, 80, 150, 255})
+
+	// Обводка
+	edge := color.RGBA{120, 100, 160, 200}
+	if s.focused {
+		edge = buttonHoverEdge
+	}
+	ebitenutil.DrawRect(screen, x, y, w, 2, edge)
+	ebitenutil.DrawRect(screen, x, y+h-2, w, 2, edge)
+	ebitenutil.DrawRect(screen, x, y, 2, h, edge)
+	ebitenutil.DrawRect(screen, x+w-2, y, 2, h, edge)
+
+	// Ползунок
+	knobX := x + filledWidth
+	knobY := y + h/2
+	knob := float64(s.knob)
+	for i := 0; i < 3; i++ {
+		size := knob + float64(i*3)
+		alpha := uint8(50 - i*15)
+		offset := size / 2
+		ebitenutil.DrawRect(screen, knobX-offset, knobY-offset, size, size, color.RGBA{150, 120, 200, alpha})
+	}
+	ebitenutil.DrawRect(screen, knobX-knob/2, knobY-knob/2, knob, knob, color.RGBA{200, 160, 255, 255})
+
+	// Процент
+	percent := fmt.Sprintf("%d%%", int(v*100))
+	pw, _ := textSize(t.BodyFont, percent)
+	px := s.rect.Min.X + s.rect.Dx()/2 - pw/2
+	py := s.rect.Max.Y + t.Px(30)
+	if s.InlineValue {
+		_, ph := textSize(t.BodyFont, percent)
+		px = s.rect.Max.X + s.knob + t.Px(8)
+		py = s.rect.Min.Y + s.rect.Dy()/2 + ph/2
+	}
+	drawTextShadow(screen, percent, t.BodyFont, px, py, t.Px(2), color.RGBA{220, 200, 255, 255}, 150)
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// slotCardPadding — отступ содержимого карточки от рамки
+const slotCardPadding = 8
+
+// SlotCard — карточка слота сохранения: миниатюра слева, справа заголовок,
+// отметка времени у правого края и строки сведений под ними. Текст уже
+// переведён: в нём даты и числа.
+type SlotCard struct {
+	Box Box
+
+	Title   string
+	Stamp   string
+	Details []string
+
+	// Thumbnail — миниатюра 16:9; без неё рисуется пустая рамка
+	Thumbnail *ebiten.Image
+
+	OnClick func()
+
+	rect    image.Rectangle
+	hovered bool
+	focused bool
+}
+
+func (c *SlotCard) Layout(t *Theme) {
+	c.rect = c.Box.Resolve(t)
+}
+
+func (c *SlotCard) Bounds() image.Rectangle {
+	return c.rect
+}
+
+func (c *SlotCard) Update(t *Theme, p Pointer) {
+	c.hovered = p.In(c.rect)
+	if c.hovered && p.Pressed {
+		c.Activate()
+	}
+}
+
+func (c *SlotCard) SetFocused(focused bool) {
+	c.focused = focused
+}
+
+func (c *SlotCard) Activate() {
+	if c.OnClick != nil {
+		c.OnClick()
+	}
+}
+
+func (c *SlotCard) Draw(screen *ebiten.Image, t *Theme) {
+	bg, border, textColor := buttonBg, buttonBorder, buttonText
+	if c.hovered || c.focused {
+		bg, border, textColor = buttonHoverBg, buttonHoverEdge, buttonActiveText
+	}
+	drawBox(screen, c.rect, bg, border)
+
+	pad := t.Px(slotCardPadding)
+	thumbH := c.rect.Dy() - pad*2
+	thumb := image.Rect(c.rect.Min.X+pad, c.rect.Min.Y+pad, c.rect.Min.X+pad+thumbH*16/9, c.rect.Max.Y-pad)
+	c.drawThumbnail(screen, thumb)
+
+	face := t.BodyFont
+	m := face.Metrics()
+	x := thumb.Max.X + pad
+	y := c.rect.Min.Y + pad + m.Ascent.Ceil()
+
+	drawTextShadow(screen, c.Title, face, x, y, t.Px(2), textColor, 150)
+	if c.Stamp != "" {
+		w, _ := textSize(face, c.Stamp)
+		drawTextShadow(screen, c.Stamp, face, c.rect.Max.X-pad-w, y, t.Px(2), color.RGBA{180, 170, 160, 220}, 150)
+	}
+	for _, line := range c.Details {
+		y += m.Height.Ceil()
+		drawTextShadow(screen, line, face, x, y, t.Px(2), color.RGBA{180, 170, 160, 220}, 150)
+	}
+}
+
+func (c *SlotCard) drawThumbnail(screen *ebiten.Image, r image.Rectangle) {
+	ebitenutil.DrawRect(screen, float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy()), color.RGBA{20, 15, 30, 255})
+	if c.Thumbnail == nil {
+		return
+	}
+
+	w, h := c.Thumbnail.Size()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(r.Dx())/float64(w), float64(r.Dy())/float64(h))
+	op.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(c.Thumbnail, op)
+}
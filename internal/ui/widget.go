@@ -0,0 +1,148 @@
+// Package ui — сохраняемый слой интерфейса: дерево виджетов, у каждого из которых
+// прямоугольник вычисляется один раз при раскладке и используется и для отрисовки,
+// и для попадания мышью. Положение виджетов задаётся якорями экрана (Point, Box),
+// поэтому раскладка подстраивается под любой размер и пропорции экрана.
+package ui
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+)
+
+// FontRole выбирает шрифт темы для текста виджета
+type FontRole int
+
+const (
+	FontBody FontRole = iota
+	FontTitle
+)
+
+// Theme — общие для всех виджетов шрифты, перевод строк, анимация подсветки,
+// экран и масштаб интерфейса
+type Theme struct {
+	TitleFont font.Face
+	BodyFont  font.Face
+
+	// Screen — логический экран, к которому привязываются якоря
+	Screen image.Rectangle
+	// Scale — масштаб интерфейса; шрифты темы должны быть созданы с тем же масштабом
+	Scale float64
+
+	// Translate переводит ключ локализации в строку текущего языка
+	Translate func(key string) string
+
+	// Glow — текущая интенсивность пульсирующей подсветки (0.3..1.0)
+	Glow float64
+}
+
+// Px переводит пиксели интерфейса 1280×720 в пиксели экрана с учётом масштаба
+func (t *Theme) Px(n int) int {
+	if t.Scale == 0 {
+		return n
+	}
+	return int(math.Round(float64(n) * t.Scale))
+}
+
+// Face возвращает шрифт для роли
+func (t *Theme) Face(role FontRole) font.Face {
+	if role == FontTitle {
+		return t.TitleFont
+	}
+	return t.BodyFont
+}
+
+// Text переводит ключ, если задана функция перевода
+func (t *Theme) Text(key string) string {
+	if t.Translate == nil {
+		return key
+	}
+	return t.Translate(key)
+}
+
+// Pointer — состояние мыши за кадр
+type Pointer struct {
+	X, Y     int
+	Down     bool // кнопка удерживается
+	Pressed  bool // кнопка нажата в этом кадре
+	Released bool // кнопка отпущена в этом кадре
+}
+
+// In сообщает, находится ли курсор внутри прямоугольника
+func (p Pointer) In(r image.Rectangle) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X && p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// Widget — узел дерева интерфейса
+type Widget interface {
+	// Layout пересчитывает прямоугольник виджета; вызывается при построении
+	// дерева и при смене языка или шрифтов
+	Layout(t *Theme)
+	// Bounds возвращает прямоугольник, вычисленный в Layout
+	Bounds() image.Rectangle
+	// Update обрабатывает мышь; hover вычисляется по Bounds
+	Update(t *Theme, p Pointer)
+	// Draw отрисовывает виджет в прямоугольнике Bounds
+	Draw(screen *ebiten.Image, t *Theme)
+}
+
+// Focusable — виджет, который можно выбрать с клавиатуры и активировать
+type Focusable interface {
+	Widget
+	SetFocused(focused bool)
+	Activate()
+}
+
+// Adjustable — виджет со значением, которое меняется стрелками влево/вправо
+type Adjustable interface {
+	Step(delta float64)
+}
+
+// Panel — контейнер виджетов; его прямоугольник охватывает всех детей
+type Panel struct {
+	Children []Widget
+	rect     image.Rectangle
+}
+
+func (p *Panel) Add(children ...Widget) {
+	p.Children = append(p.Children, children...)
+}
+
+func (p *Panel) Layout(t *Theme) {
+	p.rect = image.Rectangle{}
+	for _, c := range p.Children {
+		c.Layout(t)
+		p.rect = p.rect.Union(c.Bounds())
+	}
+}
+
+func (p *Panel) Bounds() image.Rectangle {
+	return p.rect
+}
+
+func (p *Panel) Update(t *Theme, ptr Pointer) {
+	for _, c := range p.Children {
+		c.Update(t, ptr)
+	}
+}
+
+func (p *Panel) Draw(screen *ebiten.Image, t *Theme) {
+	for _, c := range p.Children {
+		c.Draw(screen, t)
+	}
+}
+
+// focusables собирает фокусируемые виджеты дерева в порядке обхода
+func (p *Panel) focusables(out []Focusable) []Focusable {
+	for _, c := range p.Children {
+		if f, ok := c.(Focusable); ok {
+			out = append(out, f)
+		}
+		if sub, ok := c.(*Panel); ok {
+			out = sub.focusables(out)
+		}
+	}
+	return out
+}
@@ -0,0 +1,294 @@
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"io/fs"
+)
+
+// AVISource — Motion JPEG в контейнере AVI: каждый кадр — отдельный JPEG,
+// поэтому кадры декодируются независимо и стандартной библиотекой.
+// Такой файл получается из любого ролика, например, командой
+//
+//	ffmpeg -i source.mp4 -vf scale=640:-2 -c:v mjpeg -q:v 5 -an menu-background-video.avi
+//
+// Motion JPEG сжимает хуже кодеков со сжатием между кадрами, поэтому фону
+// меню хватает 640×360: на экране он всё равно растягивается.
+type AVISource struct {
+	file io.Closer
+	r    io.ReaderAt
+	size int64 // размер файла
+
+	width, height int
+	frameRate     float64
+	chunks        []aviChunk
+	buf           []byte
+}
+
+// MaxFrameBytes — предел сжатого кадра: больше не бывает даже у JPEG
+// наибольшего кадра с наилучшим качеством, а чужой файл не заставит
+// выделить гигабайты
+const MaxFrameBytes = 16 << 20
+
+// aviChunk — положение сжатого кадра в файле
+type aviChunk struct {
+	offset int64
+	size   uint32
+}
+
+// aviStream — то, что нужно знать о видеопотоке из заголовков hdrl
+type aviStream struct {
+	index   int
+	handler string
+	scale   uint32
+	rate    uint32
+	width   int32
+	height  int32
+}
+
+// OpenAVI читает заголовки и индекс кадров AVI-файла
+func OpenAVI(fsys fs.FS, name string) (*AVISource, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &AVISource{file: f}
+	info, err := f.Stat()
+	if ra, ok := f.(io.ReaderAt); ok && err == nil {
+		s.r, s.size = ra, info.Size()
+	} else {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("video: read %s: %w", name, err)
+		}
+		s.r, s.size = bytes.NewReader(data), int64(len(data))
+	}
+
+	if err := s.parse(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("video: %s: %w", name, err)
+	}
+	return s, nil
+}
+
+func (s *AVISource) parse() error {
+	var hdr [12]byte
+	if _, err := s.r.ReadAt(hdr[:], 0); err != nil {
+		return fmt.Errorf("read RIFF header: %w", err)
+	}
+	if string(hdr[0:4]) != "RIFF" || string(hdr[8:12]) != "AVI " {
+		return fmt.Errorf("not an AVI file")
+	}
+	end := 8 + int64(binary.LittleEndian.Uint32(hdr[4:8]))
+
+	var video *aviStream
+	streams := 0
+	var movi []aviChunk
+
+	err := s.walk(12, end, func(id string, offset int64, size uint32, listType string) error {
+		switch {
+		case id == "LIST" && listType == "hdrl":
+			return s.walk(offset+4, offset+int64(size), func(id string, offset int64, size uint32, listType string) error {
+				if id != "LIST" || listType != "strl" {
+					return nil
+				}
+				st, err := s.readStream(offset+4, offset+int64(size), streams)
+				streams++
+				if err != nil {
+					return err
+				}
+				if st != nil && video == nil {
+					video = st
+				}
+				return nil
+			})
+		case id == "LIST" && listType == "movi":
+			moviEnd := offset + int64(size)
+			var collect func(id string, offset int64, size uint32, listType string) error
+			collect = func(id string, offset int64, size uint32, listType string) error {
+				if id == "LIST" && listType == "rec " {
+					return s.walk(offset+4, offset+int64(size), collect)
+				}
+				if !isVideoChunk(id, video) {
+					return nil
+				}
+				switch {
+				case offset+int64(size) > moviEnd:
+					return fmt.Errorf("%w: frame chunk at %d overruns movi list", ErrCorrupt, offset)
+				case size > MaxFrameBytes:
+					return fmt.Errorf("%w: frame chunk at %d is %d bytes", ErrCorrupt, offset, size)
+				case offset+int64(size) > s.size:
+					// Кадр обрезан вместе с файлом; предыдущие годятся
+					return nil
+				}
+				movi = append(movi, aviChunk{offset: offset, size: size})
+				return nil
+			}
+			return s.walk(offset+4, moviEnd, collect)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if video == nil {
+		return fmt.Errorf("no video stream")
+	}
+	if video.handler != "MJPG" {
+		return fmt.Errorf("%w: codec %q (only Motion JPEG is supported)", ErrUnsupported, video.handler)
+	}
+
+	// Пустые чанки — повтор предыдущего кадра; пропускаем их
+	for _, c := range movi {
+		if c.size > 0 {
+			s.chunks = append(s.chunks, c)
+		}
+	}
+	if len(s.chunks) == 0 {
+		return fmt.Errorf("no video frames")
+	}
+
+	// Отрицательная высота — кадры сверху вниз, для JPEG это ничего не меняет
+	s.width, s.height = int(video.width), int(abs32(video.height))
+	if s.width <= 0 || s.height <= 0 || s.width > MaxFrameSide || s.height > MaxFrameSide {
+		return fmt.Errorf("%w: frame size %dx%d", ErrCorrupt, video.width, video.height)
+	}
+	if video.scale > 0 {
+		s.frameRate = float64(video.rate) / float64(video.scale)
+	}
+	return nil
+}
+
+// walk перебирает чанки RIFF в диапазоне [offset, end). Для LIST передаётся
+// тип списка, а offset указывает на него (данные списка начинаются с offset+4).
+func (s *AVISource) walk(offset, end int64, fn func(id string, offset int64, size uint32, listType string) error) error {
+	// Списки обрезанного файла заявляют больше, чем в нём осталось
+	if end > s.size {
+		end = s.size
+	}
+	var hdr [12]byte
+	for offset+8 <= end {
+		n, err := s.r.ReadAt(hdr[:], offset)
+		if n < 8 {
+			if err == io.EOF {
+				// Обрезанный файл: берём то, что успели прочитать
+				return nil
+			}
+			return fmt.Errorf("read chunk at %d: %w", offset, err)
+		}
+
+		id := string(hdr[0:4])
+		size := binary.LittleEndian.Uint32(hdr[4:8])
+		listType := ""
+		if (id == "LIST" || id == "RIFF") && n >= 12 {
+			listType = string(hdr[8:12])
+		}
+
+		if err := fn(id, offset+8, size, listType); err != nil {
+			return err
+		}
+
+		// Чанки выравниваются по двум байтам
+		offset += 8 + int64(size) + int64(size&1)
+	}
+	return nil
+}
+
+// readStream разбирает список strl; для потоков, кроме видео, возвращает nil
+func (s *AVISource) readStream(offset, end int64, index int) (*aviStream, error) {
+	st := &aviStream{index: index}
+	isVideo := false
+
+	err := s.walk(offset, end, func(id string, offset int64, size uint32, listType string) error {
+		switch id {
+		case "strh":
+			var h [32]byte
+			if size < uint32(len(h)) {
+				return fmt.Errorf("short strh")
+			}
+			if _, err := s.r.ReadAt(h[:], offset); err != nil {
+				return err
+			}
+			isVideo = string(h[0:4]) == "vids"
+			st.handler = string(h[4:8])
+			st.scale = binary.LittleEndian.Uint32(h[20:24])
+			st.rate = binary.LittleEndian.Uint32(h[24:28])
+		case "strf":
+			// BITMAPINFOHEADER: biSize, biWidth, biHeight, biPlanes, biBitCount, biCompression
+			var h [20]byte
+			if size < uint32(len(h)) {
+				return nil
+			}
+			if _, err := s.r.ReadAt(h[:], offset); err != nil {
+				return err
+			}
+			st.width = int32(binary.LittleEndian.Uint32(h[4:8]))
+			st.height = int32(binary.LittleEndian.Uint32(h[8:12]))
+			if compression := string(h[16:20]); isVideo && compression == "MJPG" {
+				st.handler = compression
+			}
+		}
+		return nil
+	})
+	if err != nil || !isVideo {
+		return nil, err
+	}
+	return st, nil
+}
+
+// isVideoChunk сообщает, что чанк movi — кадр видеопотока: "NNdc" или "NNdb"
+func isVideoChunk(id string, video *aviStream) bool {
+	if video == nil || len(id) != 4 || (id[2:] != "dc" && id[2:] != "db") {
+		return false
+	}
+	return id[:2] == fmt.Sprintf("%02d", video.index)
+}
+
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func (s *AVISource) Size() (int, int) {
+	return s.width, s.height
+}
+
+func (s *AVISource) FrameRate() float64 {
+	return s.frameRate
+}
+
+func (s *AVISource) FrameCount() int {
+	return len(s.chunks)
+}
+
+func (s *AVISource) ReadFrame(i int, dst *image.RGBA) error {
+	c := s.chunks[i]
+	if cap(s.buf) < int(c.size) {
+		s.buf = make([]byte, c.size)
+	}
+	data := s.buf[:c.size]
+	if _, err := s.r.ReadAt(data, c.offset); err != nil && err != io.EOF {
+		return fmt.Errorf("video: read frame %d: %w", i, err)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(withHuffmanTables(data)))
+	if err != nil {
+		return fmt.Errorf("video: decode frame %d: %w", i, err)
+	}
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+	return nil
+}
+
+func (s *AVISource) Close() error {
+	return s.file.Close()
+}
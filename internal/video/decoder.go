@@ -0,0 +1,278 @@
+// Package video декодирует кадры фонового видео в отдельной горутине.
+// Кадры берутся из FrameSource — каталога картинок или Motion JPEG в AVI.
+// Декодер заранее готовит несколько кадров в ограниченном кольцевом буфере,
+// а главный поток только забирает готовые RGBA-пиксели и загружает их в текстуру.
+package video
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"sync/atomic"
+)
+
+// Значения Options по умолчанию
+const (
+	DefaultBufferFrames = 16
+	DefaultMemoryBudget = 32 << 20
+)
+
+// minBufferFrames — меньше двух кадров буфер не сглаживает ничего
+const minBufferFrames = 2
+
+// MaxFrameSide — наибольшая сторона кадра в пикселях; больше не вытянет
+// ни текстура, ни буфер декодера
+const MaxFrameSide = 8192
+
+// Options — размер кольцевого буфера декодера
+type Options struct {
+	// BufferFrames — сколько кадров декодировать наперёд
+	BufferFrames int
+	// MemoryBudget — предел памяти под пиксели буфера в байтах; если кадры
+	// крупные, буфер получается короче BufferFrames
+	MemoryBudget int64
+	// Once — проиграть ролик один раз; по умолчанию он повторяется по кругу
+	Once bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.BufferFrames <= 0 {
+		o.BufferFrames = DefaultBufferFrames
+	}
+	if o.MemoryBudget <= 0 {
+		o.MemoryBudget = DefaultMemoryBudget
+	}
+	return o
+}
+
+// Frame — декодированный кадр. Seq растёт без ограничений и на повторах,
+// Index — номер кадра в ролике.
+type Frame struct {
+	Seq   int64
+	Index int
+	Image *image.RGBA
+}
+
+// Stats — состояние декодера для отладочного оверлея
+type Stats struct {
+	Buffered     int    // готовых кадров в буфере
+	Capacity     int    // ёмкость буфера в кадрах
+	Decoded      uint64 // всего декодировано кадров
+	Dropped      uint64 // кадров пропущено из-за отставания декодера
+	Errors       uint64 // кадров, которые не удалось декодировать
+	MemoryBytes  int64  // выделено памяти под пиксели
+	MemoryBudget int64
+}
+
+// Decoder декодирует кадры источника по кругу в фоновой горутине
+type Decoder struct {
+	src   FrameSource
+	count int
+
+	width, height int
+	frameBytes    int64
+	capacity      int
+	budget        int64
+	once          bool
+
+	frames  chan Frame       // готовые кадры, кольцевой буфер
+	free    chan *image.RGBA // буферы пикселей для повторного использования
+	buffers int64            // сколько буферов выделено
+
+	pending *Frame // кадр, пришедший раньше своего времени
+	want    int64  // самый ранний Seq, который ещё нужен главному потоку
+
+	decoded uint64
+	dropped uint64
+	errors  uint64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDecoder запускает декодирование кадров src; декодер владеет источником
+// и закрывает его в Close. Источник с пустым или слишком большим кадром
+// либо без кадров — ошибка, тогда источник закрывает вызвавший.
+func NewDecoder(src FrameSource, opts Options) (*Decoder, error) {
+	opts = opts.withDefaults()
+
+	width, height := src.Size()
+	if width <= 0 || height <= 0 || width > MaxFrameSide || height > MaxFrameSide {
+		return nil, fmt.Errorf("video: bad frame size %dx%d", width, height)
+	}
+	if src.FrameCount() <= 0 {
+		return nil, fmt.Errorf("video: no frames")
+	}
+	frameBytes := int64(width) * int64(height) * 4
+	capacity := opts.BufferFrames
+	// Кроме буфера память занимают кадр, который сейчас декодируется, и отложенный кадр
+	if byBudget := int(opts.MemoryBudget/frameBytes) - 2; byBudget < capacity {
+		capacity = byBudget
+	}
+	if capacity < minBufferFrames {
+		capacity = minBufferFrames
+	}
+
+	d := &Decoder{
+		src:        src,
+		count:      src.FrameCount(),
+		width:      width,
+		height:     height,
+		frameBytes: frameBytes,
+		capacity:   capacity,
+		budget:     opts.MemoryBudget,
+		once:       opts.Once,
+		frames:     make(chan Frame, capacity),
+		// Буфер кадров плюс кадр, который декодер заполняет, и отложенный кадр
+		free: make(chan *image.RGBA, capacity+2),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go d.run()
+	return d, nil
+}
+
+// Size возвращает размер кадров в пикселях
+func (d *Decoder) Size() (int, int) {
+	return d.width, d.height
+}
+
+// FrameCount возвращает число кадров в ролике
+func (d *Decoder) FrameCount() int {
+	return d.count
+}
+
+func (d *Decoder) run() {
+	defer close(d.done)
+
+	// Если подряд не декодировался ни один кадр ролика, дальше пытаться бессмысленно
+	failed := 0
+	for seq := int64(0); ; seq++ {
+		// Главный поток уже ушёл вперёд: пропускаем кадры, не декодируя их
+		if want := atomic.LoadInt64(&d.want); seq < want {
+			atomic.AddUint64(&d.dropped, uint64(want-seq))
+			seq = want
+		}
+
+		if d.once && seq >= int64(d.count) {
+			<-d.stop
+			return
+		}
+
+		buf := d.buffer()
+		if buf == nil {
+			return
+		}
+
+		index := int(seq % int64(d.count))
+		if err := d.src.ReadFrame(index, buf); err != nil {
+			atomic.AddUint64(&d.errors, 1)
+			d.free <- buf
+			if failed++; failed >= d.count {
+				return
+			}
+			continue
+		}
+		failed = 0
+
+		select {
+		case d.frames <- Frame{Seq: seq, Index: index, Image: buf}:
+			atomic.AddUint64(&d.decoded, 1)
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// buffer берёт свободный буфер пикселей или выделяет новый, пока их меньше
+// ёмкости пула; nil означает, что декодер остановлен
+func (d *Decoder) buffer() *image.RGBA {
+	select {
+	case buf := <-d.free:
+		return buf
+	default:
+	}
+
+	if atomic.LoadInt64(&d.buffers) < int64(cap(d.free)) {
+		atomic.AddInt64(&d.buffers, 1)
+		return image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+	}
+
+	select {
+	case buf := <-d.free:
+		return buf
+	case <-d.stop:
+		return nil
+	}
+}
+
+// Acquire возвращает самый свежий готовый кадр не позже seq. Если декодер
+// отстаёт, это может быть кадр, время которого уже прошло: показать его лучше,
+// чем не показать ничего. Более старые готовые кадры возвращаются в пул
+// и считаются пропущенными. ok == false — новых кадров нет, и вызывающий
+// продолжает показывать прежний. Полученный кадр нужно вернуть через Release.
+func (d *Decoder) Acquire(seq int64) (frame Frame, ok bool) {
+	atomic.StoreInt64(&d.want, seq)
+
+	for {
+		var f Frame
+		if d.pending != nil {
+			f = *d.pending
+			d.pending = nil
+		} else {
+			select {
+			case f = <-d.frames:
+			default:
+				return frame, ok
+			}
+		}
+
+		if f.Seq > seq {
+			d.pending = &f
+			return frame, ok
+		}
+		if ok {
+			atomic.AddUint64(&d.dropped, 1)
+			d.Release(frame)
+		}
+		frame, ok = f, true
+		if f.Seq == seq {
+			return frame, ok
+		}
+	}
+}
+
+// Release возвращает буфер кадра декодеру
+func (d *Decoder) Release(f Frame) {
+	if f.Image == nil {
+		return
+	}
+	select {
+	case d.free <- f.Image:
+	default:
+	}
+}
+
+// Stats возвращает счётчики декодера
+func (d *Decoder) Stats() Stats {
+	return Stats{
+		Buffered:     len(d.frames),
+		Capacity:     d.capacity,
+		Decoded:      atomic.LoadUint64(&d.decoded),
+		Dropped:      atomic.LoadUint64(&d.dropped),
+		Errors:       atomic.LoadUint64(&d.errors),
+		MemoryBytes:  atomic.LoadInt64(&d.buffers) * d.frameBytes,
+		MemoryBudget: d.budget,
+	}
+}
+
+// Close останавливает горутину декодера, дожидается её завершения
+// и закрывает источник
+func (d *Decoder) Close() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+		<-d.done
+		d.src.Close()
+	})
+}
@@ -0,0 +1,94 @@
+package video
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DirSource — ролик, разложенный на картинки в одном каталоге; кадры идут
+// в порядке имён файлов
+type DirSource struct {
+	fsys          fs.FS
+	paths         []string
+	width, height int
+}
+
+// OpenDir собирает кадры .png, .jpg и .jpeg из каталога dir
+func OpenDir(fsys fs.FS, dir string) (*DirSource, error) {
+	files, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("video: read frames directory: %w", err)
+	}
+
+	var paths []string
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		switch strings.ToLower(path.Ext(file.Name())) {
+		case ".png", ".jpg", ".jpeg":
+			paths = append(paths, path.Join(dir, file.Name()))
+		}
+	}
+	sort.Strings(paths)
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("video: no frames found in %s", dir)
+	}
+
+	f, err := fsys.Open(paths[0])
+	if err != nil {
+		return nil, fmt.Errorf("video: open first frame: %w", err)
+	}
+	cfg, _, err := image.DecodeConfig(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("video: read first frame: %w", err)
+	}
+
+	return &DirSource{
+		fsys:   fsys,
+		paths:  paths,
+		width:  cfg.Width,
+		height: cfg.Height,
+	}, nil
+}
+
+func (s *DirSource) Size() (int, int) {
+	return s.width, s.height
+}
+
+// FrameRate у каталога картинок неизвестна
+func (s *DirSource) FrameRate() float64 {
+	return 0
+}
+
+func (s *DirSource) FrameCount() int {
+	return len(s.paths)
+}
+
+func (s *DirSource) ReadFrame(i int, dst *image.RGBA) error {
+	f, err := s.fsys.Open(s.paths[i])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("video: decode %s: %w", s.paths[i], err)
+	}
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+	return nil
+}
+
+func (s *DirSource) Close() error {
+	return nil
+}
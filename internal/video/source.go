@@ -0,0 +1,50 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ErrUnsupported — контейнер или кодек, который нечем декодировать без внешних библиотек
+var ErrUnsupported = errors.New("video: unsupported format")
+
+// ErrCorrupt — заголовки файла противоречат сами себе или его размеру
+var ErrCorrupt = errors.New("video: file is corrupted")
+
+// FrameSource — источник кадров ролика с доступом по номеру кадра. Все методы,
+// кроме Close, вызываются из одной горутины декодера.
+type FrameSource interface {
+	// Size возвращает размер кадра в пикселях
+	Size() (w, h int)
+	// FrameRate возвращает частоту кадров из контейнера или 0, если она неизвестна
+	FrameRate() float64
+	// FrameCount возвращает число кадров
+	FrameCount() int
+	// ReadFrame декодирует кадр i в dst
+	ReadFrame(i int, dst *image.RGBA) error
+	Close() error
+}
+
+// Open выбирает источник по пути: каталог — последовательность картинок,
+// .avi — Motion JPEG в AVI. MP4, WebM и прочие контейнеры со сжатием между
+// кадрами возвращают ErrUnsupported.
+func Open(fsys fs.FS, name string) (FrameSource, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return OpenDir(fsys, name)
+	}
+
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".avi":
+		return OpenAVI(fsys, name)
+	default:
+		return nil, fmt.Errorf("%w: %s (convert it to Motion JPEG AVI or a frame directory)", ErrUnsupported, name)
+	}
+}
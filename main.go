@@ -1,23 +1,43 @@
 package main
 
 import (
+	"aethelgard/assets"
 	"aethelgard/internal/game"
+	"aethelgard/internal/settings"
+	"flag"
 	"log"
+	"path/filepath"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func main() {
-	ebiten.SetWindowTitle("Aethelgard: Realms Unbound")
+	assetsDir := flag.String("assets", "", "directory with game assets (default: ./assets or assets next to the executable)")
+	flag.Parse()
 
-	// Заменяем SetWindowSize на полноэкранный режим
-	ebiten.SetFullscreen(true) // Это ключевая строка!
+	// Моды лежат рядом с настройками: <каталог конфигурации>/aethelgard/mods/<мод>
+	var modsDir string
+	if dir, err := settings.Dir(); err == nil {
+		modsDir = filepath.Join(dir, "mods")
+	} else {
+		log.Printf("Warning: mods are disabled: %v", err)
+	}
 
-	// Опционально: включаем плавное масштабирование
-	ebiten.SetVsyncEnabled(true)
+	fsys, err := assets.Open(*assetsDir, modsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Asset layers: %s", strings.Join(fsys.Layers(), " > "))
+
+	ebiten.SetWindowTitle("Aethelgard: Realms Unbound")
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetWindowSizeLimits(game.MinWindowWidth, game.MinWindowHeight, -1, -1)
+
+	// Режим окна, размер и вертикальную синхронизацию задают настройки,
+	// их применяет NewGame
 
-	game := game.NewGame()
+	game := game.NewGame(fsys)
 	if err := ebiten.RunGame(game); err != nil {
 		log.Fatal(err)
 	}